@@ -0,0 +1,162 @@
+// Package main provides a webhook forwarding proxy. It verifies GitHub webhook
+// deliveries on a single public endpoint and forwards each one to the internal
+// services listed in a JSON configuration file, re-signing the body with each
+// service's own secret.
+//
+// Example configuration:
+//
+//	{
+//	  "destinations": [
+//	    {
+//	      "name": "ci",
+//	      "url": "http://ci.internal/github",
+//	      "secret_env": "CI_WEBHOOK_SECRET",
+//	      "events": ["push", "pull_request"],
+//	      "repositories": ["my-org/*"]
+//	    },
+//	    {
+//	      "name": "triage",
+//	      "url": "http://triage.internal/hook",
+//	      "secret_env": "TRIAGE_WEBHOOK_SECRET",
+//	      "events": ["issues"],
+//	      "actions": ["opened", "reopened"]
+//	    }
+//	  ]
+//	}
+//
+// The secret for incoming deliveries is read from WEBHOOK_SECRET, which must
+// be set. Deliveries are acknowledged as soon as they are verified and
+// forwarded in the background; on SIGINT or SIGTERM the proxy stops accepting
+// deliveries and waits for pending ones to finish.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go/webhook"
+)
+
+// destinationConfig is a destination as it appears in the configuration file.
+// Secrets are referenced by environment variable so the file can be committed.
+type destinationConfig struct {
+	webhook.Destination
+	SecretEnv string `json:"secret_env"`
+}
+
+// config is the structure of the configuration file.
+type config struct {
+	Destinations []destinationConfig `json:"destinations"`
+}
+
+func main() {
+	configPath := flag.String("config", "forwarder.json", "path to the destination configuration file")
+	addr := flag.String("addr", ":3000", "address to listen on")
+	route := flag.String("path", "/webhook", "path to receive deliveries on")
+	attempts := flag.Int("attempts", 3, "maximum delivery attempts per destination")
+	flag.Parse()
+
+	destinations, err := loadDestinations(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	secret := os.Getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("WEBHOOK_SECRET is not set; refusing to forward unverified deliveries")
+	}
+
+	forwarder, err := webhook.NewForwarder(secret, destinations,
+		webhook.WithRetry(*attempts, 500*time.Millisecond),
+		webhook.WithResultHandler(logResults),
+	)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(*route, forwarder)
+
+	// Create a server with timeouts
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 20 * time.Second,
+		ReadTimeout:       1 * time.Minute,
+		WriteTimeout:      2 * time.Minute,
+		IdleTimeout:       5 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Forwarding deliveries on %s%s to %d destinations...\n", *addr, *route, len(destinations))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down, finishing in-flight deliveries...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown error: %v", err)
+	}
+	// Deliveries already acknowledged to GitHub are retried to completion
+	forwarder.Wait()
+}
+
+// loadDestinations reads the configuration file and resolves each destination's secret.
+func loadDestinations(path string) ([]webhook.Destination, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is supplied by the operator
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+
+	destinations := make([]webhook.Destination, 0, len(cfg.Destinations))
+	for _, dc := range cfg.Destinations {
+		d := dc.Destination
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+		if dc.SecretEnv != "" {
+			d.Secret = os.Getenv(dc.SecretEnv)
+			if d.Secret == "" {
+				return nil, fmt.Errorf("destination %q: %s is not set", dc.Name, dc.SecretEnv)
+			}
+		}
+		destinations = append(destinations, d)
+	}
+
+	return destinations, nil
+}
+
+// logResults logs the outcome of a delivery for each destination.
+func logResults(deliveryID string, results []webhook.ForwardResult) {
+	for _, r := range results {
+		if r.OK() {
+			log.Printf("Delivery %s -> %s: %d after %d attempt(s) in %s",
+				deliveryID, r.Destination, r.StatusCode, r.Attempts, r.Duration)
+			continue
+		}
+		log.Printf("Delivery %s -> %s FAILED after %d attempt(s): %v",
+			deliveryID, r.Destination, r.Attempts, r.Err)
+	}
+}
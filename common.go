@@ -80,6 +80,11 @@ type Repository struct {
 	OpenIssues       int       `json:"open_issues"`
 	Watchers         int       `json:"watchers"`
 	DefaultBranch    string    `json:"default_branch"`
+	Topics           []string  `json:"topics,omitempty"`
+	// Visibility is public, private or internal.
+	Visibility string `json:"visibility,omitempty"`
+	// SecurityAndAnalysis is only included for users with admin access to the repository.
+	SecurityAndAnalysis *SecurityAndAnalysis `json:"security_and_analysis,omitempty"`
 }
 
 // User represents a GitHub account in webhook payloads.
@@ -120,6 +125,20 @@ type Organization struct {
 	Description      string `json:"description"`
 }
 
+// Enterprise represents a GitHub enterprise account in webhook payloads.
+type Enterprise struct {
+	ID          int64     `json:"id"`
+	NodeID      string    `json:"node_id"`
+	Slug        string    `json:"slug"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	WebsiteURL  *string   `json:"website_url"`
+	HTMLURL     string    `json:"html_url"`
+	AvatarURL   string    `json:"avatar_url"`
+	CreatedAt   Timestamp `json:"created_at"`
+	UpdatedAt   Timestamp `json:"updated_at"`
+}
+
 // License represents a license object in a repository.
 type License struct {
 	Key    string `json:"key"`
@@ -140,6 +159,9 @@ type WebhookPayload struct {
 		ID     int64  `json:"id"`
 		NodeID string `json:"node_id"`
 	} `json:"installation,omitempty"`
+	// Enterprise is set for deliveries from GitHub Enterprise Server and for
+	// enterprise-level webhooks.
+	Enterprise *Enterprise `json:"enterprise,omitempty"`
 }
 
 // Timestamp is a custom time type that correctly handles
@@ -183,3 +205,11 @@ func (t *Timestamp) UnmarshalJSON(data []byte) error {
 	// If we get here, we couldn't parse the string with any known format
 	return nil
 }
+
+// Common returns the fields shared by every webhook payload. Because each
+// payload type embeds WebhookPayload, the method is promoted to all of them,
+// which lets generic code reach the repository, sender and action without a
+// type switch.
+func (p *WebhookPayload) Common() *WebhookPayload {
+	return p
+}
@@ -18,59 +18,82 @@ type WebhookEventType string
 
 // GitHub webhook event types.
 const (
-	CheckRunEvent                     WebhookEventType = "check_run"
-	CheckSuiteEvent                   WebhookEventType = "check_suite"
-	CommitCommentEvent                WebhookEventType = "commit_comment"
-	ContentReferenceEvent             WebhookEventType = "content_reference"
-	CreateEvent                       WebhookEventType = "create"
-	DeleteEvent                       WebhookEventType = "delete"
-	DeployKeyEvent                    WebhookEventType = "deploy_key"
-	DeploymentEvent                   WebhookEventType = "deployment"
-	DeploymentStatusEvent             WebhookEventType = "deployment_status"
-	DiscussionEvent                   WebhookEventType = "discussion"
-	DiscussionCommentEvent            WebhookEventType = "discussion_comment"
-	ForkEvent                         WebhookEventType = "fork"
-	GitHubAppAuthorizationEvent       WebhookEventType = "github_app_authorization"
-	GollumEvent                       WebhookEventType = "gollum"
-	InstallationEvent                 WebhookEventType = "installation"
-	InstallationRepositoriesEvent     WebhookEventType = "installation_repositories"
-	IssueCommentEvent                 WebhookEventType = "issue_comment"
-	IssuesEvent                       WebhookEventType = "issues"
-	LabelEvent                        WebhookEventType = "label"
-	MarketplacePurchaseEvent          WebhookEventType = "marketplace_purchase"
-	MemberEvent                       WebhookEventType = "member"
-	MembershipEvent                   WebhookEventType = "membership"
-	MetaEvent                         WebhookEventType = "meta"
-	MilestoneEvent                    WebhookEventType = "milestone"
-	OrganizationEvent                 WebhookEventType = "organization"
-	OrgBlockEvent                     WebhookEventType = "org_block"
-	PackageEvent                      WebhookEventType = "package"
-	PageBuildEvent                    WebhookEventType = "page_build"
-	PingEvent                         WebhookEventType = "ping"
-	ProjectEvent                      WebhookEventType = "project"
-	ProjectCardEvent                  WebhookEventType = "project_card"
-	ProjectColumnEvent                WebhookEventType = "project_column"
-	PublicEvent                       WebhookEventType = "public"
-	PullRequestEvent                  WebhookEventType = "pull_request"
-	PullRequestReviewEvent            WebhookEventType = "pull_request_review"
-	PullRequestReviewCommentEvent     WebhookEventType = "pull_request_review_comment"
-	PushEvent                         WebhookEventType = "push"
-	ReleaseEvent                      WebhookEventType = "release"
-	RegistryPackageEvent              WebhookEventType = "registry_package"
-	RepositoryDispatchEvent           WebhookEventType = "repository_dispatch"
-	RepositoryEvent                   WebhookEventType = "repository"
-	RepositoryImportEvent             WebhookEventType = "repository_import"
-	RepositoryVulnerabilityAlertEvent WebhookEventType = "repository_vulnerability_alert"
-	SecurityAdvisoryEvent             WebhookEventType = "security_advisory"
-	SponsorshipEvent                  WebhookEventType = "sponsorship"
-	StarEvent                         WebhookEventType = "star"
-	StatusEvent                       WebhookEventType = "status"
-	TeamEvent                         WebhookEventType = "team"
-	TeamAddEvent                      WebhookEventType = "team_add"
-	WatchEvent                        WebhookEventType = "watch"
-	WorkflowDispatchEvent             WebhookEventType = "workflow_dispatch"
-	WorkflowJobEvent                  WebhookEventType = "workflow_job"
-	WorkflowRunEvent                  WebhookEventType = "workflow_run"
+	BranchProtectionConfigurationEvent WebhookEventType = "branch_protection_configuration"
+	BranchProtectionRuleEvent          WebhookEventType = "branch_protection_rule"
+	CheckRunEvent                      WebhookEventType = "check_run"
+	CheckSuiteEvent                    WebhookEventType = "check_suite"
+	CodeScanningAlertEvent             WebhookEventType = "code_scanning_alert"
+	CommitCommentEvent                 WebhookEventType = "commit_comment"
+	ContentReferenceEvent              WebhookEventType = "content_reference"
+	CreateEvent                        WebhookEventType = "create"
+	CustomPropertyEvent                WebhookEventType = "custom_property"
+	CustomPropertyValuesEvent          WebhookEventType = "custom_property_values"
+	DeleteEvent                        WebhookEventType = "delete"
+	DependabotAlertEvent               WebhookEventType = "dependabot_alert"
+	DeployKeyEvent                     WebhookEventType = "deploy_key"
+	DeploymentEvent                    WebhookEventType = "deployment"
+	DeploymentProtectionRuleEvent      WebhookEventType = "deployment_protection_rule"
+	DeploymentReviewEvent              WebhookEventType = "deployment_review"
+	DeploymentStatusEvent              WebhookEventType = "deployment_status"
+	DiscussionEvent                    WebhookEventType = "discussion"
+	DiscussionCommentEvent             WebhookEventType = "discussion_comment"
+	ForkEvent                          WebhookEventType = "fork"
+	GitHubAppAuthorizationEvent        WebhookEventType = "github_app_authorization"
+	GollumEvent                        WebhookEventType = "gollum"
+	InstallationEvent                  WebhookEventType = "installation"
+	InstallationRepositoriesEvent      WebhookEventType = "installation_repositories"
+	InstallationTargetEvent            WebhookEventType = "installation_target"
+	IssueCommentEvent                  WebhookEventType = "issue_comment"
+	IssueDependenciesEvent             WebhookEventType = "issue_dependencies"
+	IssuesEvent                        WebhookEventType = "issues"
+	LabelEvent                         WebhookEventType = "label"
+	MarketplacePurchaseEvent           WebhookEventType = "marketplace_purchase"
+	MemberEvent                        WebhookEventType = "member"
+	MembershipEvent                    WebhookEventType = "membership"
+	MergeGroupEvent                    WebhookEventType = "merge_group"
+	MetaEvent                          WebhookEventType = "meta"
+	MilestoneEvent                     WebhookEventType = "milestone"
+	OrganizationEvent                  WebhookEventType = "organization"
+	OrgBlockEvent                      WebhookEventType = "org_block"
+	PackageEvent                       WebhookEventType = "package"
+	PageBuildEvent                     WebhookEventType = "page_build"
+	PersonalAccessTokenRequestEvent    WebhookEventType = "personal_access_token_request"
+	PingEvent                          WebhookEventType = "ping"
+	ProjectEvent                       WebhookEventType = "project"
+	ProjectCardEvent                   WebhookEventType = "project_card"
+	ProjectColumnEvent                 WebhookEventType = "project_column"
+	ProjectsV2Event                    WebhookEventType = "projects_v2"
+	ProjectsV2ItemEvent                WebhookEventType = "projects_v2_item"
+	ProjectsV2StatusUpdateEvent        WebhookEventType = "projects_v2_status_update"
+	PublicEvent                        WebhookEventType = "public"
+	PullRequestEvent                   WebhookEventType = "pull_request"
+	PullRequestReviewEvent             WebhookEventType = "pull_request_review"
+	PullRequestReviewCommentEvent      WebhookEventType = "pull_request_review_comment"
+	PullRequestReviewThreadEvent       WebhookEventType = "pull_request_review_thread"
+	PushEvent                          WebhookEventType = "push"
+	ReleaseEvent                       WebhookEventType = "release"
+	RegistryPackageEvent               WebhookEventType = "registry_package"
+	RepositoryAdvisoryEvent            WebhookEventType = "repository_advisory"
+	RepositoryDispatchEvent            WebhookEventType = "repository_dispatch"
+	RepositoryEvent                    WebhookEventType = "repository"
+	RepositoryImportEvent              WebhookEventType = "repository_import"
+	RepositoryRulesetEvent             WebhookEventType = "repository_ruleset"
+	RepositoryVulnerabilityAlertEvent  WebhookEventType = "repository_vulnerability_alert"
+	SecretScanningAlertEvent           WebhookEventType = "secret_scanning_alert"
+	SecretScanningAlertLocationEvent   WebhookEventType = "secret_scanning_alert_location"
+	SecretScanningScanEvent            WebhookEventType = "secret_scanning_scan"
+	SecurityAdvisoryEvent              WebhookEventType = "security_advisory"
+	SecurityAndAnalysisEvent           WebhookEventType = "security_and_analysis"
+	SponsorshipEvent                   WebhookEventType = "sponsorship"
+	StarEvent                          WebhookEventType = "star"
+	StatusEvent                        WebhookEventType = "status"
+	SubIssuesEvent                     WebhookEventType = "sub_issues"
+	TeamEvent                          WebhookEventType = "team"
+	TeamAddEvent                       WebhookEventType = "team_add"
+	WatchEvent                         WebhookEventType = "watch"
+	WorkflowDispatchEvent              WebhookEventType = "workflow_dispatch"
+	WorkflowJobEvent                   WebhookEventType = "workflow_job"
+	WorkflowRunEvent                   WebhookEventType = "workflow_run"
 )
 
 // WebhookEventHeader is the HTTP header key used to determine the webhook event type.
@@ -85,6 +108,14 @@ const WebhookSignatureHeader = "X-Hub-Signature"
 // WebhookSignatureHeader256 is the HTTP header key used for SHA-256 webhook signature validation.
 const WebhookSignatureHeader256 = "X-Hub-Signature-256"
 
+// WebhookEnterpriseVersionHeader is the HTTP header key carrying the GitHub
+// Enterprise Server version that sent the delivery, e.g. "3.14.2".
+const WebhookEnterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+
+// WebhookEnterpriseHostHeader is the HTTP header key carrying the hostname of
+// the GitHub Enterprise Server instance that sent the delivery.
+const WebhookEnterpriseHostHeader = "X-GitHub-Enterprise-Host"
+
 // GetEventType extracts the webhook event type from the HTTP request headers.
 func GetEventType(r *http.Request) WebhookEventType {
 	return WebhookEventType(r.Header.Get(WebhookEventHeader))
@@ -95,47 +126,73 @@ func GetDeliveryID(r *http.Request) string {
 	return r.Header.Get(WebhookDeliveryHeader)
 }
 
+// GetEnterpriseVersion extracts the GitHub Enterprise Server version from the
+// HTTP request headers. It is empty for deliveries from github.com.
+func GetEnterpriseVersion(r *http.Request) string {
+	return r.Header.Get(WebhookEnterpriseVersionHeader)
+}
+
+// GetEnterpriseHost extracts the GitHub Enterprise Server hostname from the
+// HTTP request headers. It is empty for deliveries from github.com.
+func GetEnterpriseHost(r *http.Request) string {
+	return r.Header.Get(WebhookEnterpriseHostHeader)
+}
+
 // WebhookEvent contains metadata about the webhook event, including its type,
 // delivery ID, and payload.
 type WebhookEvent struct {
 	Type       WebhookEventType
 	DeliveryID string
 	Payload    interface{}
+	// EnterpriseVersion and EnterpriseHost identify the GitHub Enterprise
+	// Server instance that sent the delivery. Both are empty for github.com.
+	EnterpriseVersion string
+	EnterpriseHost    string
 }
 
-// ParseWebhook parses a webhook from an HTTP request, identifying the event type
-// and appropriate payload structure. It returns an error if the event type is
-// unknown or if the payload cannot be parsed.
-func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
-	eventType := GetEventType(r)
-	deliveryID := GetDeliveryID(r)
-
-	// Verify we have a known event type
-	if eventType == "" {
-		return nil, fmt.Errorf("missing event type in headers")
-	}
+// IsEnterprise reports whether the event was delivered by GitHub Enterprise Server.
+func (e *WebhookEvent) IsEnterprise() bool {
+	return e.EnterpriseVersion != ""
+}
 
-	// Read and parse the request body
+// NewPayload returns a pointer to a new, empty payload struct for the given event
+// type. Unknown event types get a generic *WebhookPayload.
+func NewPayload(eventType WebhookEventType) interface{} {
 	var payload interface{}
-	var err error
 
 	switch eventType {
+	case BranchProtectionConfigurationEvent:
+		payload = new(BranchProtectionConfigurationPayload)
+	case BranchProtectionRuleEvent:
+		payload = new(BranchProtectionRulePayload)
 	case CheckRunEvent:
 		payload = new(CheckRunPayload)
 	case CheckSuiteEvent:
 		payload = new(CheckSuitePayload)
+	case CodeScanningAlertEvent:
+		payload = new(CodeScanningAlertPayload)
 	case CommitCommentEvent:
 		payload = new(CommitCommentPayload)
 	case ContentReferenceEvent:
 		payload = new(ContentReferencePayload)
 	case CreateEvent:
 		payload = new(CreatePayload)
+	case CustomPropertyEvent:
+		payload = new(CustomPropertyPayload)
+	case CustomPropertyValuesEvent:
+		payload = new(CustomPropertyValuesPayload)
 	case DeleteEvent:
 		payload = new(DeletePayload)
+	case DependabotAlertEvent:
+		payload = new(DependabotAlertPayload)
 	case DeployKeyEvent:
 		payload = new(DeployKeyPayload)
 	case DeploymentEvent:
 		payload = new(DeploymentPayload)
+	case DeploymentProtectionRuleEvent:
+		payload = new(DeploymentProtectionRulePayload)
+	case DeploymentReviewEvent:
+		payload = new(DeploymentReviewPayload)
 	case DeploymentStatusEvent:
 		payload = new(DeploymentStatusPayload)
 	case DiscussionEvent:
@@ -152,8 +209,12 @@ func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 		payload = new(InstallationPayload)
 	case InstallationRepositoriesEvent:
 		payload = new(InstallationRepositoriesPayload)
+	case InstallationTargetEvent:
+		payload = new(InstallationTargetPayload)
 	case IssueCommentEvent:
 		payload = new(IssueCommentPayload)
+	case IssueDependenciesEvent:
+		payload = new(IssueDependenciesPayload)
 	case IssuesEvent:
 		payload = new(IssuesPayload)
 	case LabelEvent:
@@ -164,6 +225,8 @@ func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 		payload = new(MemberPayload)
 	case MembershipEvent:
 		payload = new(MembershipPayload)
+	case MergeGroupEvent:
+		payload = new(MergeGroupPayload)
 	case MetaEvent:
 		payload = new(MetaPayload)
 	case MilestoneEvent:
@@ -176,6 +239,8 @@ func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 		payload = new(PackagePayload)
 	case PageBuildEvent:
 		payload = new(PageBuildPayload)
+	case PersonalAccessTokenRequestEvent:
+		payload = new(PersonalAccessTokenRequestPayload)
 	case PingEvent:
 		payload = new(PingPayload)
 	case ProjectEvent:
@@ -184,6 +249,12 @@ func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 		payload = new(ProjectCardPayload)
 	case ProjectColumnEvent:
 		payload = new(ProjectColumnPayload)
+	case ProjectsV2Event:
+		payload = new(ProjectsV2Payload)
+	case ProjectsV2ItemEvent:
+		payload = new(ProjectsV2ItemPayload)
+	case ProjectsV2StatusUpdateEvent:
+		payload = new(ProjectsV2StatusUpdatePayload)
 	case PublicEvent:
 		payload = new(PublicPayload)
 	case PullRequestEvent:
@@ -192,28 +263,44 @@ func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 		payload = new(PullRequestReviewPayload)
 	case PullRequestReviewCommentEvent:
 		payload = new(PullRequestReviewCommentPayload)
+	case PullRequestReviewThreadEvent:
+		payload = new(PullRequestReviewThreadPayload)
 	case PushEvent:
 		payload = new(PushPayload)
 	case ReleaseEvent:
 		payload = new(ReleasePayload)
 	case RegistryPackageEvent:
 		payload = new(RegistryPackagePayload)
+	case RepositoryAdvisoryEvent:
+		payload = new(RepositoryAdvisoryPayload)
 	case RepositoryDispatchEvent:
 		payload = new(RepositoryDispatchPayload)
 	case RepositoryEvent:
 		payload = new(RepositoryPayload)
 	case RepositoryImportEvent:
 		payload = new(RepositoryImportPayload)
+	case RepositoryRulesetEvent:
+		payload = new(RepositoryRulesetPayload)
 	case RepositoryVulnerabilityAlertEvent:
 		payload = new(RepositoryVulnerabilityAlertPayload)
+	case SecretScanningAlertEvent:
+		payload = new(SecretScanningAlertPayload)
+	case SecretScanningAlertLocationEvent:
+		payload = new(SecretScanningAlertLocationPayload)
+	case SecretScanningScanEvent:
+		payload = new(SecretScanningScanPayload)
 	case SecurityAdvisoryEvent:
 		payload = new(SecurityAdvisoryPayload)
+	case SecurityAndAnalysisEvent:
+		payload = new(SecurityAndAnalysisPayload)
 	case SponsorshipEvent:
 		payload = new(SponsorshipPayload)
 	case StarEvent:
 		payload = new(StarPayload)
 	case StatusEvent:
 		payload = new(StatusPayload)
+	case SubIssuesEvent:
+		payload = new(SubIssuesPayload)
 	case TeamEvent:
 		payload = new(TeamPayload)
 	case TeamAddEvent:
@@ -231,16 +318,36 @@ func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
 		payload = new(WebhookPayload)
 	}
 
+	return payload
+}
+
+// ParseWebhook parses a webhook from an HTTP request, identifying the event type
+// and appropriate payload structure. It returns an error if the event type is
+// unknown or if the payload cannot be parsed.
+func ParseWebhook(r *http.Request) (*WebhookEvent, error) {
+	eventType := GetEventType(r)
+	deliveryID := GetDeliveryID(r)
+
+	// Verify we have a known event type
+	if eventType == "" {
+		return nil, fmt.Errorf("missing event type in headers")
+	}
+
+	// Read and parse the request body
+	payload := NewPayload(eventType)
+
 	// Decode the JSON payload
 	decoder := json.NewDecoder(r.Body)
-	if err = decoder.Decode(&payload); err != nil {
+	if err := decoder.Decode(&payload); err != nil {
 		return nil, fmt.Errorf("failed to parse webhook payload: %v", err)
 	}
 
 	return &WebhookEvent{
-		Type:       eventType,
-		DeliveryID: deliveryID,
-		Payload:    payload,
+		Type:              eventType,
+		DeliveryID:        deliveryID,
+		Payload:           payload,
+		EnterpriseVersion: GetEnterpriseVersion(r),
+		EnterpriseHost:    GetEnterpriseHost(r),
 	}, nil
 }
 
@@ -573,7 +573,7 @@ func HandleGithubWebhook(c *gin.Context) {
 			Str("action", webhook.Action).
 			Str("workflow_name", webhook.WorkflowJob.Name).
 			Str("status", webhook.WorkflowJob.Status).
-			Str("conclusion", webhook.WorkflowJob.Conclusion).
+			Interface("conclusion", webhook.WorkflowJob.Conclusion).
 			Int64("run_id", webhook.WorkflowJob.RunID).
 			Str("repo", webhook.Repository.FullName).
 			Msg("Workflow job event received")
@@ -584,7 +584,7 @@ func HandleGithubWebhook(c *gin.Context) {
 			Str("workflow_name", webhook.WorkflowRun.Name).
 			Str("event", webhook.WorkflowRun.Event).
 			Str("status", webhook.WorkflowRun.Status).
-			Str("conclusion", webhook.WorkflowRun.Conclusion).
+			Interface("conclusion", webhook.WorkflowRun.Conclusion).
 			Str("repo", webhook.Repository.FullName).
 			Msg("Workflow run event received")
 
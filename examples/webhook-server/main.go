@@ -4,42 +4,36 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/ren3gadem4rm0t/github-hook-types-go"
-	"github.com/ren3gadem4rm0t/github-hook-types-go/webhook"
+	"github.com/ren3gadem4rm0t/github-hook-types-go/webhook/server"
 )
 
 func main() {
-	// Get webhook secret from environment
-	secret := os.Getenv("WEBHOOK_SECRET")
-
-	// Create a new webhook handler
-	handler := webhook.NewHandler(secret)
-
-	// Set up a webhook handler endpoint
-	http.HandleFunc("/api/webhook/github", handler.HandleWebhook(handleGitHubEvent))
-
-	// Start the server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	// Load the server configuration (PORT, WEBHOOK_SECRET, ...) from the environment
+	cfg, err := server.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if cfg.Path == "" {
+		cfg.Path = "/api/webhook/github"
 	}
 
-	// Create a server with timeouts
-	server := &http.Server{
-		Addr:              ":" + port,
-		ReadHeaderTimeout: 20 * time.Second,
-		ReadTimeout:       1 * time.Minute,
-		WriteTimeout:      2 * time.Minute,
-		IdleTimeout:       5 * time.Minute,
+	// Create a server with hardened timeouts, /healthz and /readyz. Set
+	// WEBHOOK_ALLOW_UNSIGNED=true to run locally without a secret.
+	srv, err := server.New(cfg, handleGitHubEvent)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
-	log.Printf("Server starting on port %s...\n", port)
-	log.Fatal(server.ListenAndServe())
+	log.Printf("Server starting on %s...\n", cfg.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+	log.Println("Server stopped")
 }
 
 // handleGitHubEvent processes GitHub webhook events
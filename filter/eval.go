@@ -0,0 +1,611 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// object stands in for a non-null struct or map value. It is truthy but never
+// equal to any literal.
+type object struct{}
+
+var (
+	timestampType  = reflect.TypeOf(github.Timestamp{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// node is an element of a compiled expression.
+type node interface {
+	// check validates field paths against the payload type.
+	check(t reflect.Type) error
+	// eval evaluates the node against a payload value.
+	eval(v reflect.Value) interface{}
+}
+
+type orNode struct{ left, right node }
+
+func (n *orNode) check(t reflect.Type) error {
+	if err := n.left.check(t); err != nil {
+		return err
+	}
+	return n.right.check(t)
+}
+
+func (n *orNode) eval(v reflect.Value) interface{} {
+	return truthy(n.left.eval(v)) || truthy(n.right.eval(v))
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) check(t reflect.Type) error {
+	if err := n.left.check(t); err != nil {
+		return err
+	}
+	return n.right.check(t)
+}
+
+func (n *andNode) eval(v reflect.Value) interface{} {
+	return truthy(n.left.eval(v)) && truthy(n.right.eval(v))
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) check(t reflect.Type) error {
+	return n.operand.check(t)
+}
+
+func (n *notNode) eval(v reflect.Value) interface{} {
+	return !truthy(n.operand.eval(v))
+}
+
+type literalNode struct{ value interface{} }
+
+func (n *literalNode) check(reflect.Type) error {
+	return nil
+}
+
+func (n *literalNode) eval(reflect.Value) interface{} {
+	return n.value
+}
+
+// stringValue returns the literal as a string, if it is one.
+func (n *literalNode) stringValue() (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	s, ok := n.value.(string)
+	return s, ok
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+	re          *regexp.Regexp
+}
+
+func (n *compareNode) check(t reflect.Type) error {
+	if err := n.left.check(t); err != nil {
+		return err
+	}
+	if err := n.right.check(t); err != nil {
+		return err
+	}
+
+	// Only a field compared with a literal can be checked up front
+	fieldOperand, litOperand := n.left, n.right
+	if _, ok := fieldOperand.(*pathNode); !ok {
+		fieldOperand, litOperand = litOperand, fieldOperand
+	}
+	field, ok := fieldOperand.(*pathNode)
+	if !ok {
+		return nil
+	}
+	lit, ok := litOperand.(*literalNode)
+	if !ok {
+		return nil
+	}
+	fieldOnLeft := fieldOperand == n.left
+
+	fieldKind, list := field.kind(t)
+	if fieldKind == kindAny {
+		return nil
+	}
+
+	switch n.op {
+	case "==", "!=":
+		if items, ok := lit.value.([]interface{}); ok && list {
+			return field.checkLiterals(fieldKind, n.op, items)
+		}
+		return field.checkLiteral(fieldKind, n.op, lit.value)
+	case "<", "<=", ">", ">=":
+		if fieldKind != kindNumber && fieldKind != kindString {
+			return fmt.Errorf("%q is %s and cannot be used with %s", field.source, fieldKind, n.op)
+		}
+		return field.checkLiteral(fieldKind, n.op, lit.value)
+	case "in":
+		if !fieldOnLeft {
+			if !list {
+				return fmt.Errorf("%q is not a list and cannot be used on the right of in", field.source)
+			}
+			return field.checkLiteral(fieldKind, n.op, lit.value)
+		}
+		items, ok := lit.value.([]interface{})
+		if !ok {
+			return fmt.Errorf("in requires a list on the right of %q", field.source)
+		}
+		return field.checkLiterals(fieldKind, n.op, items)
+	case "contains":
+		if !fieldOnLeft {
+			return nil
+		}
+		if !list && fieldKind != kindString {
+			return fmt.Errorf("%q is %s and cannot be used with contains", field.source, fieldKind)
+		}
+		return field.checkLiteral(fieldKind, n.op, lit.value)
+	case "matches", "=~":
+		if fieldOnLeft && fieldKind != kindString {
+			return fmt.Errorf("%q is %s and cannot be used with %s", field.source, fieldKind, n.op)
+		}
+	}
+	return nil
+}
+
+func (n *compareNode) eval(v reflect.Value) interface{} {
+	left := n.left.eval(v)
+	right := n.right.eval(v)
+
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "<", "<=", ">", ">=":
+		return order(n.op, left, right)
+	case "in":
+		list, ok := right.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if equal(left, item) {
+				return true
+			}
+		}
+		return false
+	case "contains":
+		switch l := left.(type) {
+		case string:
+			s, ok := right.(string)
+			return ok && strings.Contains(l, s)
+		case []interface{}:
+			for _, item := range l {
+				if equal(item, right) {
+					return true
+				}
+			}
+		}
+		return false
+	case "matches":
+		pattern, _ := right.(string)
+		return anyString(left, func(s string) bool {
+			ok, _ := path.Match(pattern, s)
+			return ok
+		})
+	case "=~":
+		return anyString(left, n.re.MatchString)
+	}
+	return false
+}
+
+type pathNode struct {
+	source   string
+	segments []string
+}
+
+func (n *pathNode) check(t reflect.Type) error {
+	for i, seg := range n.segments {
+		t = elemType(t)
+
+		switch {
+		case t == nil || t.Kind() == reflect.Interface || t == rawMessageType:
+			// Untyped JSON; anything below here is resolved at evaluation time
+			return nil
+		case t == timestampType:
+			return fmt.Errorf("unknown field %q in %q: timestamps have no fields", seg, n.source)
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			t = t.Elem()
+		case t.Kind() == reflect.Struct:
+			field, ok := fieldByJSONName(t, seg)
+			if !ok {
+				return fmt.Errorf("unknown field %q in %q", strings.Join(n.segments[:i+1], "."), n.source)
+			}
+			t = field.Type
+		default:
+			return fmt.Errorf("unknown field %q in %q: %s has no fields", seg, n.source, strings.Join(n.segments[:i], "."))
+		}
+	}
+	return nil
+}
+
+func (n *pathNode) eval(v reflect.Value) interface{} {
+	return resolve(v, n.segments)
+}
+
+// kind returns the kind of value the path yields in payload type t and
+// whether the path passes through a list. The path must already have been
+// checked.
+func (n *pathNode) kind(t reflect.Type) (valueKind, bool) {
+	list := false
+	leaf := func() {
+		for t != nil && t != rawMessageType && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			if t.Kind() != reflect.Ptr {
+				list = true
+			}
+			t = t.Elem()
+		}
+	}
+
+	for _, seg := range n.segments {
+		leaf()
+		switch {
+		case t == nil || t.Kind() == reflect.Interface || t == rawMessageType:
+			return kindAny, list
+		case t.Kind() == reflect.Map:
+			t = t.Elem()
+		default:
+			field, _ := fieldByJSONName(t, seg)
+			t = field.Type
+		}
+	}
+	leaf()
+
+	return kindOf(t), list
+}
+
+// checkLiteral reports an error if a literal can never compare equal to or be
+// ordered against a value of the field's kind. Null is accepted for any field.
+func (n *pathNode) checkLiteral(fieldKind valueKind, op string, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	if litKind := literalKind(value); litKind != fieldKind {
+		return fmt.Errorf("%q is %s and cannot be compared with %s using %s", n.source, fieldKind, litKind, op)
+	}
+	return nil
+}
+
+// checkLiterals applies checkLiteral to every item of a list literal.
+func (n *pathNode) checkLiterals(fieldKind valueKind, op string, items []interface{}) error {
+	for _, item := range items {
+		if err := n.checkLiteral(fieldKind, op, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valueKind classifies the values a field or literal can hold for compile-time
+// type checking.
+type valueKind int
+
+const (
+	kindAny valueKind = iota
+	kindBool
+	kindNumber
+	kindString
+	kindList
+	kindObject
+)
+
+func (k valueKind) String() string {
+	switch k {
+	case kindBool:
+		return "a boolean"
+	case kindNumber:
+		return "a number"
+	case kindString:
+		return "a string"
+	case kindList:
+		return "a list"
+	case kindObject:
+		return "an object"
+	}
+	return "untyped"
+}
+
+// kindOf classifies a leaf field type. Timestamps are compared as strings.
+func kindOf(t reflect.Type) valueKind {
+	if t == nil || t == rawMessageType {
+		return kindAny
+	}
+	if t == timestampType {
+		return kindString
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return kindBool
+	case reflect.String:
+		return kindString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kindNumber
+	case reflect.Struct, reflect.Map:
+		return kindObject
+	}
+	return kindAny
+}
+
+// literalKind classifies a literal value.
+func literalKind(value interface{}) valueKind {
+	switch value.(type) {
+	case bool:
+		return kindBool
+	case float64:
+		return kindNumber
+	case string:
+		return kindString
+	case []interface{}:
+		return kindList
+	}
+	return kindAny
+}
+
+// elemType strips pointers and slices from a type so that paths may pass
+// through lists and nullable values.
+func elemType(t reflect.Type) reflect.Type {
+	for t != nil && t != rawMessageType && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	return t
+}
+
+// fieldByJSONName finds a struct field by its JSON name, following embedded
+// structs the same way encoding/json does. Fields declared directly on the
+// struct take precedence over promoted ones.
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	var embedded []reflect.StructField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && tagName == "" {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tagName == "" {
+			tagName = f.Name
+		}
+		if tagName == name {
+			return f, true
+		}
+	}
+
+	for _, e := range embedded {
+		et := e.Type
+		if et.Kind() == reflect.Ptr {
+			et = et.Elem()
+		}
+		if et.Kind() != reflect.Struct {
+			continue
+		}
+		if f, ok := fieldByJSONName(et, name); ok {
+			f.Index = append([]int{e.Index[0]}, f.Index...)
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// resolve walks a field path through a value and returns the normalised result.
+func resolve(v reflect.Value, segments []string) interface{} {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type() == rawMessageType {
+		if v.Len() == 0 {
+			return nil
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(v.Bytes(), &decoded); err != nil {
+			return nil
+		}
+		return resolve(reflect.ValueOf(decoded), segments)
+	}
+
+	if len(segments) == 0 {
+		return normalize(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		// Map the rest of the path over every element
+		var out []interface{}
+		for i := 0; i < v.Len(); i++ {
+			switch r := resolve(v.Index(i), segments).(type) {
+			case nil:
+			case []interface{}:
+				out = append(out, r...)
+			default:
+				out = append(out, r)
+			}
+		}
+		return out
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		return resolve(v.MapIndex(reflect.ValueOf(segments[0]).Convert(v.Type().Key())), segments[1:])
+	case reflect.Struct:
+		if v.Type() == timestampType {
+			return nil
+		}
+		field, ok := fieldByJSONName(v.Type(), segments[0])
+		if !ok {
+			return nil
+		}
+		fv, err := v.FieldByIndexErr(field.Index)
+		if err != nil {
+			return nil
+		}
+		return resolve(fv, segments[1:])
+	}
+
+	return nil
+}
+
+// normalize converts a leaf value into one of nil, bool, float64, string,
+// []interface{} or object.
+func normalize(v reflect.Value) interface{} {
+	if v.Type() == timestampType {
+		ts := v.Interface().(github.Timestamp)
+		if ts.IsZero() {
+			return nil
+		}
+		return ts.UTC().Format(time.RFC3339)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.Slice, reflect.Array:
+		out := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if item := resolve(v.Index(i), nil); item != nil {
+				out = append(out, item)
+			}
+		}
+		return out
+	case reflect.Map, reflect.Struct:
+		if v.Kind() == reflect.Map && v.IsNil() {
+			return nil
+		}
+		return object{}
+	}
+	return nil
+}
+
+// truthy reports whether a value counts as true in a boolean context.
+func truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case float64:
+		return x != 0
+	case []interface{}:
+		return len(x) > 0
+	}
+	return true
+}
+
+// equal compares two normalised values.
+func equal(a, b interface{}) bool {
+	switch x := a.(type) {
+	case nil:
+		return b == nil
+	case bool, string, float64:
+		return a == b
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// order evaluates an ordering comparison between two numbers or two strings.
+func order(op string, a, b interface{}) bool {
+	var cmp int
+	switch x := a.(type) {
+	case float64:
+		y, ok := b.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(x, y)
+	default:
+		return false
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// anyString applies fn to a string, or to each string in a list, and reports
+// whether it returned true for any of them.
+func anyString(v interface{}, fn func(string) bool) bool {
+	switch x := v.(type) {
+	case string:
+		return fn(x)
+	case []interface{}:
+		for _, item := range x {
+			if s, ok := item.(string); ok && fn(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+// Package filter implements a small, declarative expression language for
+// selecting GitHub webhook events without writing Go.
+//
+// An expression is evaluated against a single payload. Field paths mirror the
+// JSON tags of the payload, so the same names used in GitHub's documentation
+// work here:
+//
+//	action in ["opened", "synchronize"]
+//		and pull_request.base.ref == "main"
+//		and not pull_request.draft
+//		and pull_request.labels.name contains "deploy"
+//
+// The language supports:
+//
+//   - string, number, boolean and null literals, and lists of literals
+//   - comparisons: ==, !=, <, <=, >, >=
+//   - membership: x in [..], list contains x, string contains substring
+//   - glob matching with "matches" and regular expressions with =~
+//   - boolean logic with and, or, not and parentheses
+//
+// A path that passes through a list, such as pull_request.labels.name, yields the
+// list of values found in each element. A bare path used as a condition is true
+// when its value is non-zero: true, a non-empty string or list, a non-zero number
+// or a non-null object.
+//
+// Expressions are validated against the payload type for the event when they are
+// compiled, so a misspelt field, or a literal of the wrong kind such as
+// pull_request.number == "5", is reported up front rather than silently never
+// matching.
+package filter
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// Filter is a compiled filter expression bound to a single event type.
+type Filter struct {
+	eventType github.WebhookEventType
+	source    string
+	root      node
+}
+
+// Compile parses expr and validates every field path against the payload type
+// of eventType, along with the kind of every literal a field is compared with.
+// An empty expression matches every event of that type. Unknown event types
+// are rejected.
+func Compile(eventType github.WebhookEventType, expr string) (*Filter, error) {
+	if eventType == "" {
+		return nil, fmt.Errorf("filter: missing event type")
+	}
+
+	payload := github.NewPayload(eventType)
+	if _, generic := payload.(*github.WebhookPayload); generic {
+		return nil, fmt.Errorf("filter: unknown event type %q", eventType)
+	}
+
+	root, err := parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("filter: %v", err)
+	}
+
+	if root != nil {
+		if err := root.check(reflect.TypeOf(payload)); err != nil {
+			return nil, fmt.Errorf("filter: %v", err)
+		}
+	}
+
+	return &Filter{
+		eventType: eventType,
+		source:    expr,
+		root:      root,
+	}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be compiled.
+// It simplifies initialisation of package-level filters.
+func MustCompile(eventType github.WebhookEventType, expr string) *Filter {
+	f, err := Compile(eventType, expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// EventType returns the event type the filter was compiled for.
+func (f *Filter) EventType() github.WebhookEventType {
+	return f.eventType
+}
+
+// String returns the source expression of the filter.
+func (f *Filter) String() string {
+	return f.source
+}
+
+// Match reports whether the event is of the filter's event type and its payload
+// satisfies the expression.
+func (f *Filter) Match(event *github.WebhookEvent) bool {
+	if event == nil || event.Type != f.eventType {
+		return false
+	}
+	return f.MatchPayload(event.Payload)
+}
+
+// MatchPayload reports whether the payload satisfies the expression. The
+// payload is not checked against the filter's event type.
+func (f *Filter) MatchPayload(payload interface{}) bool {
+	if f.root == nil {
+		return true
+	}
+	return truthy(f.root.eval(reflect.ValueOf(payload)))
+}
+
+// Wrap returns a callback that only invokes next for events matching the
+// filter. Non-matching events are acknowledged and dropped. The result can be
+// passed to Handler.HandleWebhook or NewDispatcher in the webhook package.
+func (f *Filter) Wrap(next func(*github.WebhookEvent) error) func(*github.WebhookEvent) error {
+	return func(event *github.WebhookEvent) error {
+		if !f.Match(event) {
+			return nil
+		}
+		return next(event)
+	}
+}
@@ -0,0 +1,136 @@
+package filter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+const pullRequestJSON = `{
+	"action": "opened",
+	"number": 5,
+	"pull_request": {
+		"number": 5,
+		"draft": false,
+		"created_at": "2024-05-01T10:00:00Z",
+		"merged_at": null,
+		"labels": [{"name": "deploy"}, {"name": "bug"}],
+		"base": {"ref": "main"},
+		"head": {"ref": "feature/login"}
+	},
+	"repository": {"full_name": "octo/hello"}
+}`
+
+func pullRequestFixture(t *testing.T) *github.WebhookEvent {
+	t.Helper()
+	var payload github.PullRequestPayload
+	if err := json.Unmarshal([]byte(pullRequestJSON), &payload); err != nil {
+		t.Fatalf("decoding fixture: %v", err)
+	}
+	return &github.WebhookEvent{Type: github.PullRequestEvent, Payload: &payload}
+}
+
+func TestFilterMatch(t *testing.T) {
+	event := pullRequestFixture(t)
+
+	for expr, want := range map[string]bool{
+		``:                                              true,
+		`action == "opened"`:                            true,
+		`action in ["opened", "synchronize"]`:           true,
+		`action in ["closed"]`:                          false,
+		`pull_request.number == 5`:                      true,
+		`pull_request.number >= 6`:                      false,
+		`not pull_request.draft`:                        true,
+		`pull_request.merged_at == null`:                true,
+		`pull_request.created_at < "2025-01-01"`:        true,
+		`pull_request.labels.name contains "deploy"`:    true,
+		`pull_request.labels.name == ["deploy", "bug"]`: true,
+		`"bug" in pull_request.labels.name`:             true,
+		`pull_request.labels.name matches "dep*"`:       true,
+		`pull_request.head.ref =~ "^feature/"`:          true,
+		`repository.full_name contains "hello"`:         true,
+		`pull_request.base.ref == "main" and not (action == "closed" or pull_request.draft)`: true,
+		`pull_request.base.ref != "main" or pull_request.number > 10`:                        false,
+	} {
+		f, err := Compile(github.PullRequestEvent, expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", expr, err)
+			continue
+		}
+		if got := f.Match(event); got != want {
+			t.Errorf("%q matched = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestFilterMatchChecksEventType(t *testing.T) {
+	f := MustCompile(github.IssuesEvent, "")
+	if f.Match(pullRequestFixture(t)) {
+		t.Fatal("an issues filter matched a pull_request event")
+	}
+	if f.Match(nil) {
+		t.Fatal("a filter matched a nil event")
+	}
+}
+
+func TestFilterWrap(t *testing.T) {
+	var called int
+	next := func(*github.WebhookEvent) error {
+		called++
+		return nil
+	}
+
+	event := pullRequestFixture(t)
+	if err := MustCompile(github.PullRequestEvent, `pull_request.draft`).Wrap(next)(event); err != nil {
+		t.Fatalf("wrapped callback: %v", err)
+	}
+	if err := MustCompile(github.PullRequestEvent, `not pull_request.draft`).Wrap(next)(event); err != nil {
+		t.Fatalf("wrapped callback: %v", err)
+	}
+	if called != 1 {
+		t.Fatalf("callback ran %d times, want 1", called)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, tc := range []struct {
+		eventType github.WebhookEventType
+		expr      string
+		want      string
+	}{
+		{"", `action == "opened"`, "missing event type"},
+		{"not_an_event", `action == "opened"`, "unknown event type"},
+		{github.PullRequestEvent, `pull_request.nmber == 5`, `unknown field "pull_request.nmber"`},
+		{github.PullRequestEvent, `pull_request.created_at.year == 5`, "timestamps have no fields"},
+		{github.PullRequestEvent, `pull_request.number.value == 5`, "has no fields"},
+		{github.PullRequestEvent, `pull_request.number == "5"`, "cannot be compared with a string"},
+		{github.PullRequestEvent, `pull_request.draft > 1`, "cannot be used with >"},
+		{github.PullRequestEvent, `pull_request.draft == "true"`, "cannot be compared with a string"},
+		{github.PullRequestEvent, `action in ["opened", 1]`, "cannot be compared with a number"},
+		{github.PullRequestEvent, `action in "opened"`, "requires a list"},
+		{github.PullRequestEvent, `pull_request.number contains 5`, "cannot be used with contains"},
+		{github.PullRequestEvent, `pull_request.labels.name contains 1`, "cannot be compared with a number"},
+		{github.PullRequestEvent, `pull_request.number matches "5*"`, "cannot be used with matches"},
+		{github.PullRequestEvent, `"x" in action`, "not a list"},
+	} {
+		_, err := Compile(tc.eventType, tc.expr)
+		if err == nil {
+			t.Errorf("Compile(%q, %q) succeeded, want an error containing %q", tc.eventType, tc.expr, tc.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Compile(%q, %q) error = %q, want it to contain %q", tc.eventType, tc.expr, err, tc.want)
+		}
+	}
+}
+
+func TestMustCompilePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustCompile did not panic on an invalid expression")
+		}
+	}()
+	MustCompile(github.PullRequestEvent, `pull_request.number == "5"`)
+}
@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := lex(`a.b_c >= -1.5 and x =~ 'it\'s' or [1, "two"] != (y)`)
+	if err != nil {
+		t.Fatalf("lex: %v", err)
+	}
+
+	want := []token{
+		{tokIdent, "a.b_c", 0},
+		{tokOp, ">=", 6},
+		{tokNumber, "-1.5", 9},
+		{tokIdent, "and", 14},
+		{tokIdent, "x", 18},
+		{tokOp, "=~", 20},
+		{tokString, "it's", 23},
+		{tokIdent, "or", 31},
+		{tokLBracket, "[", 34},
+		{tokNumber, "1", 35},
+		{tokComma, ",", 36},
+		{tokString, "two", 38},
+		{tokRBracket, "]", 43},
+		{tokOp, "!=", 45},
+		{tokLParen, "(", 48},
+		{tokIdent, "y", 49},
+		{tokRParen, ")", 50},
+		{tokEOF, "", 51},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Fatalf("lex tokens:\n got %v\nwant %v", tokens, want)
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	for _, src := range []string{
+		`a = b`,
+		`!a`,
+		`"unterminated`,
+		`'trailing\`,
+		`a # b`,
+	} {
+		if _, err := lex(src); err == nil {
+			t.Errorf("lex(%q) succeeded, want an error", src)
+		}
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	n, err := parse(`not a or b and c == 1`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	or, ok := n.(*orNode)
+	if !ok {
+		t.Fatalf("root is %T, want *orNode", n)
+	}
+	if _, ok := or.left.(*notNode); !ok {
+		t.Fatalf("left of or is %T, want *notNode", or.left)
+	}
+	and, ok := or.right.(*andNode)
+	if !ok {
+		t.Fatalf("right of or is %T, want *andNode", or.right)
+	}
+	cmp, ok := and.right.(*compareNode)
+	if !ok || cmp.op != "==" {
+		t.Fatalf("right of and is %#v, want an == comparison", and.right)
+	}
+	if lit, ok := cmp.right.(*literalNode); !ok || lit.value != 1.0 {
+		t.Fatalf("comparison operand is %#v, want literal 1", cmp.right)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	n, err := parse("  ")
+	if err != nil || n != nil {
+		t.Fatalf("parse of blank expression = %v, %v; want nil, nil", n, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for src, want := range map[string]string{
+		`a ==`:            "unexpected end",
+		`(a`:              "expected ')'",
+		`a b`:             `unexpected "b"`,
+		`[a]`:             "list elements must be literals",
+		`[1 2]`:           "expected ',' or ']'",
+		`a =~ b`:          "requires a string literal",
+		`a =~ "("`:        "invalid regular expression",
+		`a matches 1`:     "requires a string literal",
+		`a matches "[" `:  "invalid glob pattern",
+		`a..b`:            "invalid field path",
+		`and`:             "unexpected keyword",
+		`a == 1.2.3`:      "invalid number",
+		`a == 1 or or b`:  "unexpected keyword",
+		`a == "x" and )`:  `unexpected ")"`,
+		`contains == "x"`: "unexpected keyword",
+	} {
+		_, err := parse(src)
+		if err == nil {
+			t.Errorf("parse(%q) succeeded, want an error containing %q", src, want)
+			continue
+		}
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("parse(%q) error = %q, want it to contain %q", src, err, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GHESVersion is a GitHub Enterprise Server feature release, such as 3.14.
+// Patch releases do not add webhook events or fields, so they are ignored.
+// Compare it with AtLeast to gate on events or fields that older servers do
+// not send.
+type GHESVersion struct {
+	Major int
+	Minor int
+}
+
+// ParseGHESVersion parses a version as sent in the X-GitHub-Enterprise-Version
+// header, e.g. "3.14.2" or "3.14".
+func ParseGHESVersion(version string) (GHESVersion, error) {
+	parts := strings.SplitN(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".", 3)
+	if len(parts) < 2 {
+		return GHESVersion{}, fmt.Errorf("invalid GitHub Enterprise Server version %q", version)
+	}
+
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return GHESVersion{}, fmt.Errorf("invalid GitHub Enterprise Server version %q: %v", version, err)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return GHESVersion{}, fmt.Errorf("invalid GitHub Enterprise Server version %q: %v", version, err)
+	}
+	return GHESVersion{Major: major, Minor: minor}, nil
+}
+
+// String returns the version formatted as "major.minor".
+func (v GHESVersion) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// AtLeast reports whether v is the same release as other or a later one.
+func (v GHESVersion) AtLeast(other GHESVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	return v.Minor >= other.Minor
+}
+
+// ghesEventSince lists the first GitHub Enterprise Server release that sends
+// each event. Events not listed are available on every supported release.
+// Entries are grouped by release, each citing that release's notes.
+var ghesEventSince = map[WebhookEventType]GHESVersion{
+	// https://docs.github.com/en/enterprise-server@3.0/admin/release-notes
+	CodeScanningAlertEvent:   {3, 0},
+	SecretScanningAlertEvent: {3, 0},
+	WorkflowDispatchEvent:    {3, 0},
+	WorkflowRunEvent:         {3, 0},
+
+	// https://docs.github.com/en/enterprise-server@3.1/admin/release-notes
+	DeploymentReviewEvent: {3, 1},
+
+	// https://docs.github.com/en/enterprise-server@3.3/admin/release-notes
+	SecurityAndAnalysisEvent: {3, 3},
+	WorkflowJobEvent:         {3, 3},
+
+	// https://docs.github.com/en/enterprise-server@3.4/admin/release-notes
+	BranchProtectionRuleEvent: {3, 4},
+	InstallationTargetEvent:   {3, 4},
+
+	// https://docs.github.com/en/enterprise-server@3.5/admin/release-notes
+	PullRequestReviewThreadEvent: {3, 5},
+
+	// https://docs.github.com/en/enterprise-server@3.8/admin/release-notes
+	DependabotAlertEvent:             {3, 8},
+	SecretScanningAlertLocationEvent: {3, 8},
+
+	// https://docs.github.com/en/enterprise-server@3.9/admin/release-notes
+	RepositoryAdvisoryEvent: {3, 9},
+
+	// https://docs.github.com/en/enterprise-server@3.10/admin/release-notes
+	DeploymentProtectionRuleEvent:   {3, 10},
+	PersonalAccessTokenRequestEvent: {3, 10},
+	ProjectsV2ItemEvent:             {3, 10},
+
+	// https://docs.github.com/en/enterprise-server@3.11/admin/release-notes
+	BranchProtectionConfigurationEvent: {3, 11},
+	ProjectsV2Event:                    {3, 11},
+	RepositoryRulesetEvent:             {3, 11},
+
+	// https://docs.github.com/en/enterprise-server@3.12/admin/release-notes
+	CustomPropertyEvent:       {3, 12},
+	CustomPropertyValuesEvent: {3, 12},
+	MergeGroupEvent:           {3, 12},
+
+	// https://docs.github.com/en/enterprise-server@3.15/admin/release-notes
+	ProjectsV2StatusUpdateEvent: {3, 15},
+	SecretScanningScanEvent:     {3, 15},
+
+	// https://docs.github.com/en/enterprise-server@3.17/admin/release-notes
+	SubIssuesEvent: {3, 17},
+
+	// https://docs.github.com/en/enterprise-server@3.18/admin/release-notes
+	IssueDependenciesEvent: {3, 18},
+}
+
+// ghesField identifies a payload field by event type and JSON path. An empty
+// event type matches the field on every event.
+type ghesField struct {
+	event WebhookEventType
+	path  string
+}
+
+// ghesFieldSince lists the first GitHub Enterprise Server release that includes
+// each field. Fields not listed are included by every release that sends the
+// event, and no field is listed as older than its event. Entries are grouped by
+// release, each citing that release's notes.
+var ghesFieldSince = map[ghesField]GHESVersion{
+	// https://docs.github.com/en/enterprise-server@3.0/admin/release-notes
+	{"", "enterprise"}: {3, 0},
+
+	// https://docs.github.com/en/enterprise-server@3.1/admin/release-notes
+	{PullRequestEvent, "pull_request.auto_merge"}:       {3, 1},
+	{CheckSuiteEvent, "check_suite.rerequestable"}:      {3, 1},
+	{CheckSuiteEvent, "check_suite.runs_rerequestable"}: {3, 1},
+
+	// https://docs.github.com/en/enterprise-server@3.3/admin/release-notes
+	{"", "repository.security_and_analysis"}: {3, 3},
+	{DeploymentEvent, "workflow_run"}:        {3, 3},
+	{DeploymentStatusEvent, "workflow_run"}:  {3, 3},
+	{DeploymentStatusEvent, "check_run"}:     {3, 3},
+
+	// https://docs.github.com/en/enterprise-server@3.4/admin/release-notes
+	{WorkflowJobEvent, "workflow_job.runner_group_id"}: {3, 4},
+	{WorkflowRunEvent, "workflow_run.run_attempt"}:     {3, 4},
+
+	// https://docs.github.com/en/enterprise-server@3.5/admin/release-notes
+	{WorkflowJobEvent, "workflow_job.run_attempt"}:      {3, 5},
+	{WorkflowRunEvent, "workflow_run.triggering_actor"}: {3, 5},
+
+	// https://docs.github.com/en/enterprise-server@3.6/admin/release-notes
+	{IssuesEvent, "issue.state_reason"}:                          {3, 6},
+	{IssueCommentEvent, "issue.state_reason"}:                    {3, 6},
+	{WorkflowRunEvent, "workflow_run.referenced_workflows"}:      {3, 6},
+	{SecretScanningAlertEvent, "alert.push_protection_bypassed"}: {3, 6},
+
+	// https://docs.github.com/en/enterprise-server@3.8/admin/release-notes
+	{WorkflowRunEvent, "workflow_run.display_title"}: {3, 8},
+
+	// https://docs.github.com/en/enterprise-server@3.9/admin/release-notes
+	{WorkflowJobEvent, "workflow_job.workflow_name"}: {3, 9},
+	{WorkflowJobEvent, "workflow_job.head_branch"}:   {3, 9},
+	{WorkflowJobEvent, "workflow_job.created_at"}:    {3, 9},
+
+	// https://docs.github.com/en/enterprise-server@3.11/admin/release-notes
+	{SecretScanningAlertEvent, "alert.validity"}: {3, 11},
+
+	// https://docs.github.com/en/enterprise-server@3.17/admin/release-notes
+	{IssuesEvent, "type"}:                           {3, 17},
+	{IssuesEvent, "issue.type"}:                     {3, 17},
+	{IssuesEvent, "issue.sub_issues_summary"}:       {3, 17},
+	{IssueCommentEvent, "issue.sub_issues_summary"}: {3, 17},
+
+	// https://docs.github.com/en/enterprise-server@3.18/admin/release-notes
+	{IssuesEvent, "issue.issue_dependencies_summary"}: {3, 18},
+}
+
+// GHESSupportsEvent reports whether a GitHub Enterprise Server release is
+// expected to send the given event. The table follows the release notes
+// of each release; events it does not list are assumed to be
+// available everywhere.
+func GHESSupportsEvent(version GHESVersion, eventType WebhookEventType) bool {
+	since, ok := ghesEventSince[eventType]
+	return !ok || version.AtLeast(since)
+}
+
+// GHESSupportsField reports whether a GitHub Enterprise Server release is
+// expected to include a payload field in the given event. The path uses the
+// payload's JSON field names separated by dots, as in "workflow_job.head_branch".
+// A field is only expected when its event is, and fields the table does not
+// list are assumed to be present whenever the event is.
+func GHESSupportsField(version GHESVersion, eventType WebhookEventType, path string) bool {
+	if !GHESSupportsEvent(version, eventType) {
+		return false
+	}
+	if since, ok := ghesFieldSince[ghesField{eventType, path}]; ok {
+		return version.AtLeast(since)
+	}
+	if since, ok := ghesFieldSince[ghesField{"", path}]; ok {
+		return version.AtLeast(since)
+	}
+	return true
+}
+
+// ExpectsField reports whether the event's payload is expected to include the
+// field at path, using the GitHub Enterprise Server version the event was
+// delivered by. Every field is expected for deliveries from github.com and
+// from servers reporting an unrecognized version.
+func (e *WebhookEvent) ExpectsField(path string) bool {
+	if !e.IsEnterprise() {
+		return true
+	}
+	version, err := ParseGHESVersion(e.EnterpriseVersion)
+	if err != nil {
+		return true
+	}
+	return GHESSupportsField(version, e.Type, path)
+}
@@ -0,0 +1,97 @@
+package github
+
+import "testing"
+
+func TestParseGHESVersion(t *testing.T) {
+	for input, want := range map[string]GHESVersion{
+		"3.14.2":  {3, 14},
+		"3.14":    {3, 14},
+		" v3.9.0": {3, 9},
+	} {
+		got, err := ParseGHESVersion(input)
+		if err != nil || got != want {
+			t.Errorf("ParseGHESVersion(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"", "3", "three.14", "3.x"} {
+		if _, err := ParseGHESVersion(input); err == nil {
+			t.Errorf("ParseGHESVersion(%q) succeeded, want an error", input)
+		}
+	}
+
+	v := GHESVersion{3, 10}
+	if !v.AtLeast(GHESVersion{3, 9}) || !v.AtLeast(GHESVersion{3, 10}) || v.AtLeast(GHESVersion{3, 11}) || v.AtLeast(GHESVersion{4, 0}) {
+		t.Errorf("AtLeast comparisons for %s are wrong", v)
+	}
+}
+
+func TestGHESFieldsAreNotOlderThanTheirEvents(t *testing.T) {
+	for field, since := range ghesFieldSince {
+		if eventSince, ok := ghesEventSince[field.event]; ok && !since.AtLeast(eventSince) {
+			t.Errorf("%s %s is listed from %s, before its event (%s)", field.event, field.path, since, eventSince)
+		}
+	}
+}
+
+func TestGHESSupportsEvent(t *testing.T) {
+	for _, tc := range []struct {
+		version   GHESVersion
+		eventType WebhookEventType
+		want      bool
+	}{
+		{GHESVersion{3, 11}, MergeGroupEvent, false},
+		{GHESVersion{3, 12}, MergeGroupEvent, true},
+		{GHESVersion{4, 0}, MergeGroupEvent, true},
+		{GHESVersion{3, 2}, WorkflowJobEvent, false},
+		{GHESVersion{3, 3}, WorkflowJobEvent, true},
+		{GHESVersion{2, 22}, PushEvent, true},
+	} {
+		if got := GHESSupportsEvent(tc.version, tc.eventType); got != tc.want {
+			t.Errorf("GHESSupportsEvent(%s, %s) = %v, want %v", tc.version, tc.eventType, got, tc.want)
+		}
+	}
+}
+
+func TestGHESSupportsField(t *testing.T) {
+	for _, tc := range []struct {
+		version   GHESVersion
+		eventType WebhookEventType
+		path      string
+		want      bool
+	}{
+		{GHESVersion{3, 8}, WorkflowJobEvent, "workflow_job.head_branch", false},
+		{GHESVersion{3, 9}, WorkflowJobEvent, "workflow_job.head_branch", true},
+		// The field is only expected when its event is
+		{GHESVersion{3, 2}, WorkflowJobEvent, "workflow_job.name", false},
+		{GHESVersion{3, 3}, WorkflowJobEvent, "workflow_job.name", true},
+		// Event-scoped entries don't leak into other events
+		{GHESVersion{3, 5}, IssuesEvent, "issue.state_reason", false},
+		{GHESVersion{3, 5}, PullRequestEvent, "issue.state_reason", true},
+		// Entries without an event apply to every event
+		{GHESVersion{3, 2}, PushEvent, "repository.security_and_analysis", false},
+		{GHESVersion{3, 3}, PushEvent, "repository.security_and_analysis", true},
+		{GHESVersion{3, 0}, PushEvent, "enterprise", true},
+	} {
+		if got := GHESSupportsField(tc.version, tc.eventType, tc.path); got != tc.want {
+			t.Errorf("GHESSupportsField(%s, %s, %q) = %v, want %v", tc.version, tc.eventType, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestWebhookEventExpectsField(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		want    bool
+	}{
+		{"", true},
+		{"3.8.4", false},
+		{"3.9.0", true},
+		{"unknown", true},
+	} {
+		event := &WebhookEvent{Type: WorkflowJobEvent, EnterpriseVersion: tc.version}
+		if got := event.ExpectsField("workflow_job.head_branch"); got != tc.want {
+			t.Errorf("ExpectsField with version %q = %v, want %v", tc.version, got, tc.want)
+		}
+	}
+}
@@ -63,15 +63,34 @@ type CommitAuthor struct {
 	Username string `json:"username,omitempty"`
 }
 
-// IssuesPayload represents the webhook payload sent for issues events.
+// IssuesPayload represents the webhook payload sent for issues events. The
+// action is one of opened, edited, deleted, closed, reopened, assigned,
+// unassigned, labeled, unlabeled, milestoned, demilestoned, locked, unlocked,
+// pinned, unpinned, transferred, typed or untyped.
 type IssuesPayload struct {
 	WebhookPayload
-	Issue   Issue `json:"issue"`
+	Issue Issue `json:"issue"`
+	// Label is the label added or removed by the labeled and unlabeled actions.
+	Label *Label `json:"label,omitempty"`
+	// Assignee is the user assigned or unassigned by the assigned and unassigned actions.
+	Assignee *User `json:"assignee,omitempty"`
+	// Milestone is the milestone added or removed by the milestoned and demilestoned actions.
+	Milestone *Milestone `json:"milestone,omitempty"`
+	// Type is the issue type set or removed by the typed and untyped actions.
+	Type    *IssueType `json:"type,omitempty"`
 	Changes struct {
 		Title    *ChangedFrom `json:"title,omitempty"`
 		Body     *ChangedFrom `json:"body,omitempty"`
 		Labels   *ChangedFrom `json:"labels,omitempty"`
 		Assignee *ChangedFrom `json:"assignee,omitempty"`
+		// NewIssue and NewRepository are set by the transferred action and
+		// describe the issue in the repository it was moved to.
+		NewIssue      *Issue      `json:"new_issue,omitempty"`
+		NewRepository *Repository `json:"new_repository,omitempty"`
+		// OldIssue and OldRepository are set by the opened action when the issue
+		// was transferred from another repository.
+		OldIssue      *Issue      `json:"old_issue,omitempty"`
+		OldRepository *Repository `json:"old_repository,omitempty"`
 	} `json:"changes,omitempty"`
 }
 
@@ -107,6 +126,58 @@ type Issue struct {
 	CreatedAt         Timestamp  `json:"created_at"`
 	UpdatedAt         Timestamp  `json:"updated_at"`
 	AuthorAssociation string     `json:"author_association"`
+	// StateReason is completed, not_planned or duplicate for closed issues and
+	// reopened for reopened ones.
+	StateReason *string    `json:"state_reason,omitempty"`
+	Type        *IssueType `json:"type,omitempty"`
+	Reactions   *Reactions `json:"reactions,omitempty"`
+	// SubIssuesSummary counts the issue's sub-issues. It is nil on servers
+	// without sub-issue support.
+	SubIssuesSummary *SubIssuesSummary `json:"sub_issues_summary,omitempty"`
+	// IssueDependenciesSummary counts the issues blocking and blocked by this
+	// one. It is nil on servers without issue dependency support.
+	IssueDependenciesSummary *IssueDependenciesSummary `json:"issue_dependencies_summary,omitempty"`
+}
+
+// IssueType represents an organization-defined issue type, such as Bug or Feature.
+type IssueType struct {
+	ID          int64      `json:"id"`
+	NodeID      string     `json:"node_id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	Color       *string    `json:"color,omitempty"`
+	IsEnabled   bool       `json:"is_enabled,omitempty"`
+	CreatedAt   *Timestamp `json:"created_at,omitempty"`
+	UpdatedAt   *Timestamp `json:"updated_at,omitempty"`
+}
+
+// Reactions summarizes the reactions on an issue or comment.
+type Reactions struct {
+	URL        string `json:"url"`
+	TotalCount int    `json:"total_count"`
+	PlusOne    int    `json:"+1"`
+	MinusOne   int    `json:"-1"`
+	Laugh      int    `json:"laugh"`
+	Confused   int    `json:"confused"`
+	Heart      int    `json:"heart"`
+	Hooray     int    `json:"hooray"`
+	Eyes       int    `json:"eyes"`
+	Rocket     int    `json:"rocket"`
+}
+
+// SubIssuesSummary summarizes the progress of an issue's sub-issues.
+type SubIssuesSummary struct {
+	Total            int `json:"total"`
+	Completed        int `json:"completed"`
+	PercentCompleted int `json:"percent_completed"`
+}
+
+// IssueDependenciesSummary summarizes an issue's blocking relationships.
+type IssueDependenciesSummary struct {
+	BlockedBy      int `json:"blocked_by"`
+	Blocking       int `json:"blocking"`
+	TotalBlockedBy int `json:"total_blocked_by"`
+	TotalBlocking  int `json:"total_blocking"`
 }
 
 // Label represents a label on an issue or pull request.
@@ -145,6 +216,51 @@ type ChangedFrom struct {
 	From interface{} `json:"from"`
 }
 
+// ChangedFromString represents a changed string value in a webhook payload.
+type ChangedFromString struct {
+	From string `json:"from"`
+}
+
+// ChangedFromNullableString represents a changed string value that may previously have been null.
+type ChangedFromNullableString struct {
+	From *string `json:"from"`
+}
+
+// ChangedFromBool represents a changed boolean value in a webhook payload.
+type ChangedFromBool struct {
+	From bool `json:"from"`
+}
+
+// ChangedFromInt represents a changed integer value in a webhook payload.
+type ChangedFromInt struct {
+	From int `json:"from"`
+}
+
+// ChangedFromStrings represents a changed list of strings in a webhook payload.
+type ChangedFromStrings struct {
+	From []string `json:"from"`
+}
+
+// ChangedFromToString represents a changed string value reported with both its
+// previous and new value. Either side is nil when the value was unset.
+type ChangedFromToString struct {
+	From *string `json:"from"`
+	To   *string `json:"to"`
+}
+
+// ChangedFromToBool represents a changed boolean value reported with both its previous and new value.
+type ChangedFromToBool struct {
+	From bool `json:"from"`
+	To   bool `json:"to"`
+}
+
+// ChangedFromToTimestamp represents a changed timestamp reported with both its
+// previous and new value. Either side is nil when the value was unset.
+type ChangedFromToTimestamp struct {
+	From *Timestamp `json:"from"`
+	To   *Timestamp `json:"to"`
+}
+
 // IssueCommentPayload represents the webhook payload sent for issue_comment events.
 type IssueCommentPayload struct {
 	WebhookPayload
@@ -167,12 +283,7 @@ type Comment struct {
 	UpdatedAt             Timestamp `json:"updated_at"`
 	IssueURL              string    `json:"issue_url,omitempty"`
 	AuthorAssociation     string    `json:"author_association"`
-	PerformedViaGitHubApp *struct {
-		ID     int64  `json:"id"`
-		NodeID string `json:"node_id"`
-		Name   string `json:"name"`
-		Slug   string `json:"slug"`
-	} `json:"performed_via_github_app,omitempty"`
+	PerformedViaGitHubApp *App      `json:"performed_via_github_app,omitempty"`
 }
 
 // PullRequestPayload represents the webhook payload sent for pull_request events.
@@ -180,7 +291,24 @@ type PullRequestPayload struct {
 	WebhookPayload
 	Number      int         `json:"number"`
 	PullRequest PullRequest `json:"pull_request"`
-	Changes     struct {
+	// Label is the label added or removed by the labeled and unlabeled actions.
+	Label *Label `json:"label,omitempty"`
+	// Assignee is the user assigned or unassigned by the assigned and unassigned actions.
+	Assignee *User `json:"assignee,omitempty"`
+	// RequestedReviewer and RequestedTeam identify who was asked for, or
+	// released from, a review by the review_requested and
+	// review_request_removed actions. Exactly one of them is set.
+	RequestedReviewer *User `json:"requested_reviewer,omitempty"`
+	RequestedTeam     *Team `json:"requested_team,omitempty"`
+	// Milestone is the milestone added or removed by the milestoned and demilestoned actions.
+	Milestone *Milestone `json:"milestone,omitempty"`
+	// Before and After are the previous and new head commit SHAs for the synchronize action.
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	// Reason explains why the pull request was removed from the merge queue by
+	// the dequeued action.
+	Reason  string `json:"reason,omitempty"`
+	Changes struct {
 		Title *ChangedFrom `json:"title,omitempty"`
 		Body  *ChangedFrom `json:"body,omitempty"`
 		Base  *struct {
@@ -196,22 +324,25 @@ type PullRequestPayload struct {
 
 // PullRequest represents a GitHub pull request.
 type PullRequest struct {
-	ID                  int64             `json:"id"`
-	NodeID              string            `json:"node_id"`
-	URL                 string            `json:"url"`
-	HTMLURL             string            `json:"html_url"`
-	DiffURL             string            `json:"diff_url"`
-	PatchURL            string            `json:"patch_url"`
-	IssueURL            string            `json:"issue_url"`
-	Number              int               `json:"number"`
-	State               string            `json:"state"`
-	Locked              bool              `json:"locked"`
-	Title               string            `json:"title"`
-	Body                string            `json:"body"`
-	CreatedAt           Timestamp         `json:"created_at"`
-	UpdatedAt           Timestamp         `json:"updated_at"`
-	ClosedAt            *Timestamp        `json:"closed_at"`
-	MergedAt            *Timestamp        `json:"merged_at"`
+	ID        int64      `json:"id"`
+	NodeID    string     `json:"node_id"`
+	URL       string     `json:"url"`
+	HTMLURL   string     `json:"html_url"`
+	DiffURL   string     `json:"diff_url"`
+	PatchURL  string     `json:"patch_url"`
+	IssueURL  string     `json:"issue_url"`
+	Number    int        `json:"number"`
+	State     string     `json:"state"`
+	Locked    bool       `json:"locked"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	CreatedAt Timestamp  `json:"created_at"`
+	UpdatedAt Timestamp  `json:"updated_at"`
+	ClosedAt  *Timestamp `json:"closed_at"`
+	MergedAt  *Timestamp `json:"merged_at"`
+	// MergeCommitSHA is the SHA of the test merge commit while the pull request
+	// is open. Once merged it is the merge, squash or rebase commit on the base
+	// branch, depending on how the pull request was merged.
 	MergeCommitSHA      *string           `json:"merge_commit_sha"`
 	Assignee            *User             `json:"assignee"`
 	Assignees           []User            `json:"assignees"`
@@ -236,15 +367,27 @@ type PullRequest struct {
 	Additions           int               `json:"additions"`
 	Deletions           int               `json:"deletions"`
 	ChangedFiles        int               `json:"changed_files"`
+	// AutoMerge is set while auto-merge is enabled for the pull request.
+	AutoMerge *PullRequestAutoMerge `json:"auto_merge"`
+}
+
+// PullRequestAutoMerge represents the auto-merge settings of a pull request.
+// MergeMethod is one of merge, squash or rebase.
+type PullRequestAutoMerge struct {
+	EnabledBy     User    `json:"enabled_by"`
+	MergeMethod   string  `json:"merge_method"`
+	CommitTitle   *string `json:"commit_title"`
+	CommitMessage *string `json:"commit_message"`
 }
 
 // PullRequestBranch represents a branch in a pull request.
 type PullRequestBranch struct {
-	Label string     `json:"label"`
-	Ref   string     `json:"ref"`
-	SHA   string     `json:"sha"`
-	User  User       `json:"user"`
-	Repo  Repository `json:"repo"`
+	Label string `json:"label"`
+	Ref   string `json:"ref"`
+	SHA   string `json:"sha"`
+	User  User   `json:"user"`
+	// Repo is nil for the head branch when the fork it lives in has been deleted.
+	Repo *Repository `json:"repo"`
 }
 
 // Team represents a GitHub team.
@@ -263,9 +406,14 @@ type Team struct {
 }
 
 // CheckRunPayload represents the webhook payload sent for check_run events.
+// The action is one of created, completed, rerequested or requested_action.
 type CheckRunPayload struct {
 	WebhookPayload
 	CheckRun CheckRun `json:"check_run"`
+	// RequestedAction is the button the user clicked, for the requested_action action.
+	RequestedAction *struct {
+		Identifier string `json:"identifier"`
+	} `json:"requested_action,omitempty"`
 }
 
 // CheckRun represents a GitHub check run.
@@ -288,72 +436,72 @@ type CheckRun struct {
 		AnnotationsCount int    `json:"annotations_count"`
 		AnnotationsURL   string `json:"annotations_url"`
 	} `json:"output"`
-	Name       string `json:"name"`
-	CheckSuite struct {
-		ID           int64         `json:"id"`
-		NodeID       string        `json:"node_id"`
-		HeadBranch   string        `json:"head_branch"`
-		HeadSHA      string        `json:"head_sha"`
-		Status       string        `json:"status"`
-		Conclusion   *string       `json:"conclusion"`
-		URL          string        `json:"url"`
-		Before       string        `json:"before"`
-		After        string        `json:"after"`
-		PullRequests []PullRequest `json:"pull_requests"`
-		App          struct {
-			ID          int64     `json:"id"`
-			NodeID      string    `json:"node_id"`
-			Owner       User      `json:"owner"`
-			Name        string    `json:"name"`
-			Description string    `json:"description"`
-			ExternalURL string    `json:"external_url"`
-			HTMLURL     string    `json:"html_url"`
-			CreatedAt   Timestamp `json:"created_at"`
-			UpdatedAt   Timestamp `json:"updated_at"`
-		} `json:"app"`
-		CreatedAt Timestamp `json:"created_at"`
-		UpdatedAt Timestamp `json:"updated_at"`
-	} `json:"check_suite"`
-	App struct {
-		ID          int64     `json:"id"`
-		NodeID      string    `json:"node_id"`
-		Owner       User      `json:"owner"`
-		Name        string    `json:"name"`
-		Description string    `json:"description"`
-		ExternalURL string    `json:"external_url"`
-		HTMLURL     string    `json:"html_url"`
-		CreatedAt   Timestamp `json:"created_at"`
-		UpdatedAt   Timestamp `json:"updated_at"`
-	} `json:"app"`
+	Name         string        `json:"name"`
+	CheckSuite   CheckSuite    `json:"check_suite"`
+	App          App           `json:"app"`
 	PullRequests []PullRequest `json:"pull_requests"`
+	// Deployment is set when the check run was created by a workflow job that
+	// deploys to an environment.
+	Deployment *Deployment `json:"deployment,omitempty"`
+}
+
+// MergeGroup reports the merge queue entry a check run was created for, derived
+// from its check suite's head branch. It reports false for check runs outside
+// a merge queue.
+func (p *CheckRunPayload) MergeGroup() (MergeQueueRef, bool) {
+	return ParseMergeQueueRef(p.CheckRun.CheckSuite.HeadBranch)
 }
 
 // CheckSuitePayload represents the webhook payload sent for check_suite events.
+// The action is one of completed, requested or rerequested.
 type CheckSuitePayload struct {
 	WebhookPayload
-	CheckSuite struct {
-		ID           int64         `json:"id"`
-		NodeID       string        `json:"node_id"`
-		HeadBranch   string        `json:"head_branch"`
-		HeadSHA      string        `json:"head_sha"`
-		Status       string        `json:"status"`
-		Conclusion   *string       `json:"conclusion"`
-		URL          string        `json:"url"`
-		Before       string        `json:"before"`
-		After        string        `json:"after"`
-		PullRequests []PullRequest `json:"pull_requests"`
-		App          struct {
-			ID          int64     `json:"id"`
-			NodeID      string    `json:"node_id"`
-			Owner       User      `json:"owner"`
-			Name        string    `json:"name"`
-			Description string    `json:"description"`
-			ExternalURL string    `json:"external_url"`
-			HTMLURL     string    `json:"html_url"`
-			CreatedAt   Timestamp `json:"created_at"`
-			UpdatedAt   Timestamp `json:"updated_at"`
-		} `json:"app"`
-		CreatedAt Timestamp `json:"created_at"`
-		UpdatedAt Timestamp `json:"updated_at"`
-	} `json:"check_suite"`
+	CheckSuite CheckSuite `json:"check_suite"`
+}
+
+// CheckSuite represents a GitHub check suite. The check suite embedded in a
+// check_run payload omits the counts, re-request flags and head commit.
+type CheckSuite struct {
+	ID                   int64         `json:"id"`
+	NodeID               string        `json:"node_id"`
+	HeadBranch           string        `json:"head_branch"`
+	HeadSHA              string        `json:"head_sha"`
+	Status               string        `json:"status"`
+	Conclusion           *string       `json:"conclusion"`
+	URL                  string        `json:"url"`
+	Before               string        `json:"before"`
+	After                string        `json:"after"`
+	PullRequests         []PullRequest `json:"pull_requests"`
+	App                  App           `json:"app"`
+	CreatedAt            Timestamp     `json:"created_at"`
+	UpdatedAt            Timestamp     `json:"updated_at"`
+	LatestCheckRunsCount int           `json:"latest_check_runs_count,omitempty"`
+	CheckRunsURL         string        `json:"check_runs_url,omitempty"`
+	Rerequestable        bool          `json:"rerequestable,omitempty"`
+	RunsRerequestable    bool          `json:"runs_rerequestable,omitempty"`
+	HeadCommit           *Commit       `json:"head_commit,omitempty"`
+}
+
+// MergeGroup reports the merge queue entry a check suite was created for,
+// derived from its head branch. It reports false for check suites outside a
+// merge queue.
+func (p *CheckSuitePayload) MergeGroup() (MergeQueueRef, bool) {
+	return ParseMergeQueueRef(p.CheckSuite.HeadBranch)
+}
+
+// App represents a GitHub App.
+type App struct {
+	ID          int64                    `json:"id"`
+	NodeID      string                   `json:"node_id"`
+	Slug        string                   `json:"slug,omitempty"`
+	ClientID    string                   `json:"client_id,omitempty"`
+	Owner       User                     `json:"owner"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	ExternalURL string                   `json:"external_url"`
+	HTMLURL     string                   `json:"html_url"`
+	CreatedAt   Timestamp                `json:"created_at"`
+	UpdatedAt   Timestamp                `json:"updated_at"`
+	Permissions *InstallationPermissions `json:"permissions,omitempty"`
+	Events      []string                 `json:"events,omitempty"`
 }
@@ -1,9 +1,150 @@
 package github
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 )
 
+// BranchProtectionConfigurationPayload represents the webhook payload sent for branch_protection_configuration events.
+// The enabled and disabled actions carry no fields beyond the common ones.
+type BranchProtectionConfigurationPayload struct {
+	WebhookPayload
+}
+
+// BranchProtectionRulePayload represents the webhook payload sent for branch_protection_rule events.
+type BranchProtectionRulePayload struct {
+	WebhookPayload
+	Rule BranchProtectionRule `json:"rule"`
+	// Changes holds the previous values of the settings modified by an edited action.
+	Changes struct {
+		AdminEnforced                            *ChangedFromBool    `json:"admin_enforced,omitempty"`
+		AuthorizedActorNames                     *ChangedFromStrings `json:"authorized_actor_names,omitempty"`
+		AuthorizedActorsOnly                     *ChangedFromBool    `json:"authorized_actors_only,omitempty"`
+		AuthorizedDismissalActorsOnly            *ChangedFromBool    `json:"authorized_dismissal_actors_only,omitempty"`
+		LinearHistoryRequirementEnforcementLevel *ChangedFromString  `json:"linear_history_requirement_enforcement_level,omitempty"`
+		LockAllowsForkSync                       *ChangedFromBool    `json:"lock_allows_fork_sync,omitempty"`
+		LockBranchEnforcementLevel               *ChangedFromString  `json:"lock_branch_enforcement_level,omitempty"`
+		PullRequestReviewsEnforcementLevel       *ChangedFromString  `json:"pull_request_reviews_enforcement_level,omitempty"`
+		RequireLastPushApproval                  *ChangedFromBool    `json:"require_last_push_approval,omitempty"`
+		RequiredStatusChecks                     *ChangedFromStrings `json:"required_status_checks,omitempty"`
+		RequiredStatusChecksEnforcementLevel     *ChangedFromString  `json:"required_status_checks_enforcement_level,omitempty"`
+	} `json:"changes,omitempty"`
+}
+
+// BranchProtectionRule represents a branch protection rule. Enforcement levels
+// are one of "off", "non_admins" or "everyone".
+type BranchProtectionRule struct {
+	ID                                       int64     `json:"id"`
+	RepositoryID                             int64     `json:"repository_id"`
+	Name                                     string    `json:"name"`
+	CreatedAt                                Timestamp `json:"created_at"`
+	UpdatedAt                                Timestamp `json:"updated_at"`
+	PullRequestReviewsEnforcementLevel       string    `json:"pull_request_reviews_enforcement_level"`
+	RequiredApprovingReviewCount             int       `json:"required_approving_review_count"`
+	DismissStaleReviewsOnPush                bool      `json:"dismiss_stale_reviews_on_push"`
+	RequireCodeOwnerReview                   bool      `json:"require_code_owner_review"`
+	AuthorizedDismissalActorsOnly            bool      `json:"authorized_dismissal_actors_only"`
+	IgnoreApprovalsFromContributors          bool      `json:"ignore_approvals_from_contributors"`
+	RequireLastPushApproval                  bool      `json:"require_last_push_approval"`
+	RequiredStatusChecks                     []string  `json:"required_status_checks"`
+	RequiredStatusChecksEnforcementLevel     string    `json:"required_status_checks_enforcement_level"`
+	StrictRequiredStatusChecksPolicy         bool      `json:"strict_required_status_checks_policy"`
+	SignatureRequirementEnforcementLevel     string    `json:"signature_requirement_enforcement_level"`
+	LinearHistoryRequirementEnforcementLevel string    `json:"linear_history_requirement_enforcement_level"`
+	AdminEnforced                            bool      `json:"admin_enforced"`
+	AllowForcePushesEnforcementLevel         string    `json:"allow_force_pushes_enforcement_level"`
+	AllowDeletionsEnforcementLevel           string    `json:"allow_deletions_enforcement_level"`
+	MergeQueueEnforcementLevel               string    `json:"merge_queue_enforcement_level"`
+	RequiredDeploymentsEnforcementLevel      string    `json:"required_deployments_enforcement_level"`
+	RequiredConversationResolutionLevel      string    `json:"required_conversation_resolution_level"`
+	AuthorizedActorsOnly                     bool      `json:"authorized_actors_only"`
+	AuthorizedActorNames                     []string  `json:"authorized_actor_names"`
+	LockBranchEnforcementLevel               string    `json:"lock_branch_enforcement_level"`
+	LockAllowsForkSync                       *bool     `json:"lock_allows_fork_sync"`
+	CreateProtected                          *bool     `json:"create_protected,omitempty"`
+}
+
+// CodeScanningAlertPayload represents the webhook payload sent for code_scanning_alert events.
+type CodeScanningAlertPayload struct {
+	WebhookPayload
+	Alert CodeScanningAlert `json:"alert"`
+	// Ref is the Git reference of the code scanning alert. When the action is
+	// reopened_by_user or closed_by_user, the event was triggered by the sender
+	// and this value will be empty.
+	Ref string `json:"ref"`
+	// CommitOID is the commit SHA of the code scanning alert. When the action is
+	// reopened_by_user or closed_by_user, the event was triggered by the sender
+	// and this value will be empty.
+	CommitOID string `json:"commit_oid"`
+}
+
+// CodeScanningAlert represents a code scanning alert.
+type CodeScanningAlert struct {
+	Number             int                        `json:"number"`
+	CreatedAt          Timestamp                  `json:"created_at"`
+	UpdatedAt          *Timestamp                 `json:"updated_at"`
+	URL                string                     `json:"url"`
+	HTMLURL            string                     `json:"html_url"`
+	InstancesURL       string                     `json:"instances_url"`
+	State              string                     `json:"state"`
+	FixedAt            *Timestamp                 `json:"fixed_at"`
+	DismissedBy        *User                      `json:"dismissed_by"`
+	DismissedAt        *Timestamp                 `json:"dismissed_at"`
+	DismissedReason    *string                    `json:"dismissed_reason"`
+	DismissedComment   *string                    `json:"dismissed_comment"`
+	Rule               CodeScanningRule           `json:"rule"`
+	Tool               CodeScanningTool           `json:"tool"`
+	MostRecentInstance *CodeScanningAlertInstance `json:"most_recent_instance"`
+}
+
+// CodeScanningRule represents the rule that triggered a code scanning alert.
+type CodeScanningRule struct {
+	ID                    string   `json:"id"`
+	Name                  string   `json:"name"`
+	Severity              *string  `json:"severity"`
+	SecuritySeverityLevel *string  `json:"security_severity_level"`
+	Description           string   `json:"description"`
+	FullDescription       string   `json:"full_description"`
+	Tags                  []string `json:"tags"`
+	Help                  *string  `json:"help"`
+	HelpURI               *string  `json:"help_uri"`
+}
+
+// CodeScanningTool represents the analysis tool that raised a code scanning alert.
+type CodeScanningTool struct {
+	Name    string  `json:"name"`
+	Version *string `json:"version"`
+	GUID    *string `json:"guid"`
+}
+
+// CodeScanningAlertInstance represents an occurrence of a code scanning alert
+// on a particular ref and analysis.
+type CodeScanningAlertInstance struct {
+	Ref         string `json:"ref"`
+	AnalysisKey string `json:"analysis_key"`
+	Environment string `json:"environment"`
+	Category    string `json:"category"`
+	State       string `json:"state"`
+	CommitSHA   string `json:"commit_sha"`
+	Message     struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Location        CodeScanningLocation `json:"location"`
+	Classifications []string             `json:"classifications"`
+}
+
+// CodeScanningLocation represents the source location of a code scanning alert instance.
+type CodeScanningLocation struct {
+	Path        string `json:"path"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column"`
+	EndColumn   int    `json:"end_column"`
+}
+
 // CommitCommentPayload represents the webhook payload sent for commit_comment events.
 type CommitCommentPayload struct {
 	WebhookPayload
@@ -43,6 +184,103 @@ type CreatePayload struct {
 	PusherType   string `json:"pusher_type"`
 }
 
+// CustomPropertyPayload represents the webhook payload sent for custom_property
+// events. The action is created, deleted, updated or promoted_to_enterprise;
+// only PropertyName is set on the definition of a deleted property.
+type CustomPropertyPayload struct {
+	WebhookPayload
+	Definition CustomProperty `json:"definition"`
+}
+
+// CustomProperty represents the definition of a custom repository property.
+// ValueType is one of string, single_select, multi_select, true_false or url.
+type CustomProperty struct {
+	PropertyName          string               `json:"property_name"`
+	URL                   string               `json:"url,omitempty"`
+	SourceType            string               `json:"source_type,omitempty"`
+	ValueType             string               `json:"value_type,omitempty"`
+	Required              bool                 `json:"required,omitempty"`
+	DefaultValue          *CustomPropertyValue `json:"default_value,omitempty"`
+	Description           *string              `json:"description,omitempty"`
+	AllowedValues         []string             `json:"allowed_values,omitempty"`
+	ValuesEditableBy      *string              `json:"values_editable_by,omitempty"`
+	RequireExplicitValues bool                 `json:"require_explicit_values,omitempty"`
+}
+
+// CustomPropertyValuesPayload represents the webhook payload sent for
+// custom_property_values events, when the property values of a repository change.
+type CustomPropertyValuesPayload struct {
+	WebhookPayload
+	NewPropertyValues []CustomPropertyValueEntry `json:"new_property_values"`
+	OldPropertyValues []CustomPropertyValueEntry `json:"old_property_values"`
+}
+
+// CustomPropertyValueEntry represents the value of a single custom property on a repository.
+type CustomPropertyValueEntry struct {
+	PropertyName string              `json:"property_name"`
+	Value        CustomPropertyValue `json:"value"`
+}
+
+// CustomPropertyValue holds a custom property value, which GitHub sends as a
+// string, an array of strings for multi_select properties, or null. At most
+// one of String and Strings is set.
+type CustomPropertyValue struct {
+	String  *string
+	Strings []string
+}
+
+// IsNull reports whether the property has no value.
+func (v CustomPropertyValue) IsNull() bool {
+	return v.String == nil && v.Strings == nil
+}
+
+// Values returns the value as a list, with a single string value as its only
+// element and a null value as an empty list.
+func (v CustomPropertyValue) Values() []string {
+	if v.String != nil {
+		return []string{*v.String}
+	}
+	return v.Strings
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *CustomPropertyValue) UnmarshalJSON(data []byte) error {
+	v.String = nil
+	v.Strings = nil
+
+	switch {
+	case string(data) == "null":
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var values []string
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("invalid custom property value: %v", err)
+		}
+		if values == nil {
+			values = []string{}
+		}
+		v.Strings = values
+	default:
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("invalid custom property value: %v", err)
+		}
+		v.String = &value
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v CustomPropertyValue) MarshalJSON() ([]byte, error) {
+	if v.String != nil {
+		return json.Marshal(*v.String)
+	}
+	if v.Strings != nil {
+		return json.Marshal(v.Strings)
+	}
+	return []byte("null"), nil
+}
+
 // DeletePayload represents the webhook payload sent for delete events.
 type DeletePayload struct {
 	WebhookPayload
@@ -51,6 +289,39 @@ type DeletePayload struct {
 	PusherType string `json:"pusher_type"`
 }
 
+// DependabotAlertPayload represents the webhook payload sent for dependabot_alert events.
+type DependabotAlertPayload struct {
+	WebhookPayload
+	Alert DependabotAlert `json:"alert"`
+}
+
+// DependabotAlert represents a Dependabot alert on a vulnerable dependency.
+type DependabotAlert struct {
+	Number                int                   `json:"number"`
+	State                 string                `json:"state"`
+	Dependency            DependabotDependency  `json:"dependency"`
+	SecurityAdvisory      SecurityAdvisory      `json:"security_advisory"`
+	SecurityVulnerability SecurityVulnerability `json:"security_vulnerability"`
+	URL                   string                `json:"url"`
+	HTMLURL               string                `json:"html_url"`
+	CreatedAt             Timestamp             `json:"created_at"`
+	UpdatedAt             Timestamp             `json:"updated_at"`
+	DismissedAt           *Timestamp            `json:"dismissed_at"`
+	DismissedBy           *User                 `json:"dismissed_by"`
+	DismissedReason       *string               `json:"dismissed_reason"`
+	DismissedComment      *string               `json:"dismissed_comment"`
+	FixedAt               *Timestamp            `json:"fixed_at"`
+	AutoDismissedAt       *Timestamp            `json:"auto_dismissed_at"`
+}
+
+// DependabotDependency represents the dependency a Dependabot alert was raised for.
+type DependabotDependency struct {
+	Package      SecurityAdvisoryPackage `json:"package"`
+	ManifestPath string                  `json:"manifest_path"`
+	Scope        *string                 `json:"scope"`
+	Relationship *string                 `json:"relationship,omitempty"`
+}
+
 // DeployKeyPayload represents the webhook payload sent for deploy_key events.
 type DeployKeyPayload struct {
 	WebhookPayload
@@ -65,66 +336,194 @@ type DeployKeyPayload struct {
 	} `json:"key"`
 }
 
+// Deployment represents a deployment of a ref to an environment.
+type Deployment struct {
+	URL                   string          `json:"url"`
+	ID                    int64           `json:"id"`
+	NodeID                string          `json:"node_id"`
+	SHA                   string          `json:"sha"`
+	Ref                   string          `json:"ref"`
+	Task                  string          `json:"task"`
+	Payload               json.RawMessage `json:"payload"`
+	Environment           string          `json:"environment"`
+	Description           string          `json:"description"`
+	Creator               User            `json:"creator"`
+	CreatedAt             Timestamp       `json:"created_at"`
+	UpdatedAt             Timestamp       `json:"updated_at"`
+	StatusesURL           string          `json:"statuses_url"`
+	RepositoryURL         string          `json:"repository_url"`
+	TransientEnvironment  bool            `json:"transient_environment"`
+	ProductionEnvironment bool            `json:"production_environment"`
+	// OriginalEnvironment is the environment the deployment was first created
+	// for, before any environment redirection.
+	OriginalEnvironment   string `json:"original_environment,omitempty"`
+	PerformedViaGitHubApp *App   `json:"performed_via_github_app,omitempty"`
+}
+
+// DecodePayload decodes the deployment's free-form payload, as supplied by
+// whoever created the deployment, into v. GitHub sends the payload either as a
+// JSON object or as a string holding JSON; both forms are accepted. An absent
+// or empty payload leaves v untouched.
+func (d *Deployment) DecodePayload(v interface{}) error {
+	data := []byte(d.Payload)
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("failed to decode deployment payload: %v", err)
+		}
+		data = []byte(s)
+	}
+
+	switch string(bytes.TrimSpace(data)) {
+	case "", "null", "{}":
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode deployment payload: %v", err)
+	}
+	return nil
+}
+
 // DeploymentPayload represents the webhook payload sent for deployment events.
 type DeploymentPayload struct {
 	WebhookPayload
-	Deployment struct {
-		URL                   string          `json:"url"`
-		ID                    int64           `json:"id"`
-		NodeID                string          `json:"node_id"`
-		SHA                   string          `json:"sha"`
-		Ref                   string          `json:"ref"`
-		Task                  string          `json:"task"`
-		Payload               json.RawMessage `json:"payload"`
-		Environment           string          `json:"environment"`
-		Description           string          `json:"description"`
-		Creator               User            `json:"creator"`
-		CreatedAt             Timestamp       `json:"created_at"`
-		UpdatedAt             Timestamp       `json:"updated_at"`
-		StatusesURL           string          `json:"statuses_url"`
-		RepositoryURL         string          `json:"repository_url"`
-		TransientEnvironment  bool            `json:"transient_environment"`
-		ProductionEnvironment bool            `json:"production_environment"`
-	} `json:"deployment"`
+	Deployment Deployment `json:"deployment"`
+	// Workflow and WorkflowRun are set when the deployment was created by a
+	// GitHub Actions job.
+	Workflow    *Workflow    `json:"workflow"`
+	WorkflowRun *WorkflowRun `json:"workflow_run"`
+}
+
+// DeploymentProtectionRulePayload represents the webhook payload sent for
+// deployment_protection_rule events. It is delivered to GitHub Apps that act as
+// a custom deployment protection rule; the app approves or rejects the
+// deployment by calling DeploymentCallbackURL.
+type DeploymentProtectionRulePayload struct {
+	WebhookPayload
+	// Environment is the name of the environment being deployed to.
+	Environment string `json:"environment"`
+	// Event is the event that triggered the deployment, such as push or workflow_dispatch.
+	Event                 string        `json:"event"`
+	SHA                   string        `json:"sha,omitempty"`
+	Ref                   string        `json:"ref,omitempty"`
+	DeploymentCallbackURL string        `json:"deployment_callback_url"`
+	Deployment            *Deployment   `json:"deployment,omitempty"`
+	PullRequests          []PullRequest `json:"pull_requests"`
+}
+
+// DeploymentReviewPayload represents the webhook payload sent for deployment_review events.
+type DeploymentReviewPayload struct {
+	WebhookPayload
+	// Approver and Comment are set for the approved and rejected actions.
+	Approver *User  `json:"approver,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+	// Requestor, Reviewers and Environment are set for the requested action.
+	Requestor   *User                `json:"requestor,omitempty"`
+	Reviewers   []DeploymentReviewer `json:"reviewers,omitempty"`
+	Environment string               `json:"environment,omitempty"`
+	// Since is the time the jobs started waiting for review.
+	Since           Timestamp                `json:"since"`
+	WorkflowJobRun  *DeploymentReviewJobRun  `json:"workflow_job_run,omitempty"`
+	WorkflowJobRuns []DeploymentReviewJobRun `json:"workflow_job_runs,omitempty"`
+	WorkflowRun     *WorkflowRun             `json:"workflow_run"`
+}
+
+// DeploymentReviewer represents a user or team that can review a deployment.
+// Type is either "User" or "Team", and exactly one of User and Team is set.
+type DeploymentReviewer struct {
+	Type string `json:"type"`
+	User *User  `json:"-"`
+	Team *Team  `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes the reviewer into User or Team depending on Type.
+func (r *DeploymentReviewer) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type     string          `json:"type"`
+		Reviewer json.RawMessage `json:"reviewer"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Type = raw.Type
+	r.User = nil
+	r.Team = nil
+
+	if len(raw.Reviewer) == 0 || string(raw.Reviewer) == "null" {
+		return nil
+	}
+	switch raw.Type {
+	case "User":
+		r.User = new(User)
+		return json.Unmarshal(raw.Reviewer, r.User)
+	case "Team":
+		r.Team = new(Team)
+		return json.Unmarshal(raw.Reviewer, r.Team)
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It writes User or Team back out as the reviewer object.
+func (r DeploymentReviewer) MarshalJSON() ([]byte, error) {
+	var reviewer interface{}
+	switch {
+	case r.User != nil:
+		reviewer = r.User
+	case r.Team != nil:
+		reviewer = r.Team
+	}
+	return json.Marshal(struct {
+		Type     string      `json:"type"`
+		Reviewer interface{} `json:"reviewer"`
+	}{r.Type, reviewer})
+}
+
+// DeploymentReviewJobRun represents a workflow job waiting on a deployment review.
+type DeploymentReviewJobRun struct {
+	ID          int64      `json:"id"`
+	JobName     string     `json:"job_name,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Conclusion  *string    `json:"conclusion"`
+	Environment string     `json:"environment"`
+	HTMLURL     string     `json:"html_url"`
+	CreatedAt   *Timestamp `json:"created_at,omitempty"`
+	UpdatedAt   *Timestamp `json:"updated_at,omitempty"`
 }
 
 // DeploymentStatusPayload represents the webhook payload sent for deployment_status events.
 type DeploymentStatusPayload struct {
 	WebhookPayload
-	DeploymentStatus struct {
-		URL            string    `json:"url"`
-		ID             int64     `json:"id"`
-		NodeID         string    `json:"node_id"`
-		State          string    `json:"state"`
-		Creator        User      `json:"creator"`
-		Description    string    `json:"description"`
-		Environment    string    `json:"environment"`
-		TargetURL      string    `json:"target_url"`
-		CreatedAt      Timestamp `json:"created_at"`
-		UpdatedAt      Timestamp `json:"updated_at"`
-		DeploymentURL  string    `json:"deployment_url"`
-		RepositoryURL  string    `json:"repository_url"`
-		LogURL         string    `json:"log_url"`
-		EnvironmentURL string    `json:"environment_url"`
-	} `json:"deployment_status"`
-	Deployment struct {
-		URL                   string          `json:"url"`
-		ID                    int64           `json:"id"`
-		NodeID                string          `json:"node_id"`
-		SHA                   string          `json:"sha"`
-		Ref                   string          `json:"ref"`
-		Task                  string          `json:"task"`
-		Payload               json.RawMessage `json:"payload"`
-		Environment           string          `json:"environment"`
-		Description           string          `json:"description"`
-		Creator               User            `json:"creator"`
-		CreatedAt             Timestamp       `json:"created_at"`
-		UpdatedAt             Timestamp       `json:"updated_at"`
-		StatusesURL           string          `json:"statuses_url"`
-		RepositoryURL         string          `json:"repository_url"`
-		TransientEnvironment  bool            `json:"transient_environment"`
-		ProductionEnvironment bool            `json:"production_environment"`
-	} `json:"deployment"`
+	DeploymentStatus DeploymentStatus `json:"deployment_status"`
+	Deployment       Deployment       `json:"deployment"`
+	// CheckRun, Workflow and WorkflowRun are set when the status was reported
+	// by a GitHub Actions job.
+	CheckRun    *CheckRun    `json:"check_run"`
+	Workflow    *Workflow    `json:"workflow"`
+	WorkflowRun *WorkflowRun `json:"workflow_run"`
+}
+
+// DeploymentStatus represents a status reported for a deployment. State is one
+// of error, failure, inactive, in_progress, pending, queued, success or waiting.
+type DeploymentStatus struct {
+	URL                   string    `json:"url"`
+	ID                    int64     `json:"id"`
+	NodeID                string    `json:"node_id"`
+	State                 string    `json:"state"`
+	Creator               User      `json:"creator"`
+	Description           string    `json:"description"`
+	Environment           string    `json:"environment"`
+	TargetURL             string    `json:"target_url"`
+	CreatedAt             Timestamp `json:"created_at"`
+	UpdatedAt             Timestamp `json:"updated_at"`
+	DeploymentURL         string    `json:"deployment_url"`
+	RepositoryURL         string    `json:"repository_url"`
+	LogURL                string    `json:"log_url"`
+	EnvironmentURL        string    `json:"environment_url"`
+	PerformedViaGitHubApp *App      `json:"performed_via_github_app,omitempty"`
 }
 
 // DiscussionPayload represents the webhook payload sent for discussion events.
@@ -214,6 +613,7 @@ type ForkPayload struct {
 }
 
 // AppAuthorizationPayload represents the webhook payload sent for github_app_authorization events.
+// Its only action is revoked, and Sender is the user who revoked the app's authorization.
 type AppAuthorizationPayload struct {
 	WebhookPayload
 }
@@ -231,63 +631,225 @@ type GollumPayload struct {
 	} `json:"pages"`
 }
 
-// InstallationPayload represents the webhook payload sent for installation events.
+// InstallationPayload represents the webhook payload sent for installation
+// events. The action is one of created, deleted, suspend, unsuspend or
+// new_permissions_accepted.
 type InstallationPayload struct {
 	WebhookPayload
-	Installation struct {
-		ID                  int64     `json:"id"`
-		NodeID              string    `json:"node_id"`
-		AppID               int64     `json:"app_id"`
-		AppSlug             string    `json:"app_slug"`
-		TargetID            int64     `json:"target_id"`
-		TargetType          string    `json:"target_type"`
-		RepositorySelection string    `json:"repository_selection"`
-		Account             User      `json:"account"`
-		AccessTokensURL     string    `json:"access_tokens_url"`
-		RepositoriesURL     string    `json:"repositories_url"`
-		HTMLURL             string    `json:"html_url"`
-		CreatedAt           Timestamp `json:"created_at"`
-		UpdatedAt           Timestamp `json:"updated_at"`
-		Events              []string  `json:"events"`
-		Permissions         struct {
-			Issues       string `json:"issues"`
-			Contents     string `json:"contents"`
-			Metadata     string `json:"metadata"`
-			SingleFile   string `json:"single_file"`
-			PullRequests string `json:"pull_requests"`
-		} `json:"permissions"`
-	} `json:"installation"`
+	Installation Installation `json:"installation"`
+	// Repositories lists the repositories the installation can access. It is
+	// set for the created, deleted, suspend, unsuspend and
+	// new_permissions_accepted actions when the installation is limited to
+	// selected repositories.
+	Repositories []InstallationRepository `json:"repositories,omitempty"`
+	// Requester is the user who requested the installation, when it needed approval.
+	Requester *User `json:"requester,omitempty"`
+}
+
+// Installation represents a GitHub App installation on a user or organization account.
+type Installation struct {
+	ID                     int64                   `json:"id"`
+	NodeID                 string                  `json:"node_id"`
+	ClientID               string                  `json:"client_id,omitempty"`
+	AppID                  int64                   `json:"app_id"`
+	AppSlug                string                  `json:"app_slug"`
+	TargetID               int64                   `json:"target_id"`
+	TargetType             string                  `json:"target_type"`
+	RepositorySelection    string                  `json:"repository_selection"`
+	Account                User                    `json:"account"`
+	AccessTokensURL        string                  `json:"access_tokens_url"`
+	RepositoriesURL        string                  `json:"repositories_url"`
+	HTMLURL                string                  `json:"html_url"`
+	CreatedAt              Timestamp               `json:"created_at"`
+	UpdatedAt              Timestamp               `json:"updated_at"`
+	Events                 []string                `json:"events"`
+	Permissions            InstallationPermissions `json:"permissions"`
+	SingleFileName         *string                 `json:"single_file_name"`
+	SingleFilePaths        []string                `json:"single_file_paths,omitempty"`
+	HasMultipleSingleFiles bool                    `json:"has_multiple_single_files,omitempty"`
+	SuspendedAt            *Timestamp              `json:"suspended_at"`
+	SuspendedBy            *User                   `json:"suspended_by"`
+}
+
+// InstallationRepository represents a repository an installation has access to.
+type InstallationRepository struct {
+	ID       int64  `json:"id"`
+	NodeID   string `json:"node_id"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Private  bool   `json:"private"`
+}
+
+// InstallationPermissions holds the access level, "read", "write" or "admin",
+// granted to an installation for each GitHub App permission. Permissions that
+// were not granted are empty. Permissions added by GitHub after this type was
+// written are available in Other.
+type InstallationPermissions struct {
+	// Repository permissions.
+	Actions                    string `json:"actions,omitempty"`
+	Administration             string `json:"administration,omitempty"`
+	Attestations               string `json:"attestations,omitempty"`
+	Checks                     string `json:"checks,omitempty"`
+	Codespaces                 string `json:"codespaces,omitempty"`
+	Contents                   string `json:"contents,omitempty"`
+	ContentReferences          string `json:"content_references,omitempty"`
+	DependabotSecrets          string `json:"dependabot_secrets,omitempty"`
+	Deployments                string `json:"deployments,omitempty"`
+	Discussions                string `json:"discussions,omitempty"`
+	Environments               string `json:"environments,omitempty"`
+	Issues                     string `json:"issues,omitempty"`
+	MergeQueues                string `json:"merge_queues,omitempty"`
+	Metadata                   string `json:"metadata,omitempty"`
+	Packages                   string `json:"packages,omitempty"`
+	Pages                      string `json:"pages,omitempty"`
+	PullRequests               string `json:"pull_requests,omitempty"`
+	RepositoryAdvisories       string `json:"repository_advisories,omitempty"`
+	RepositoryCustomProperties string `json:"repository_custom_properties,omitempty"`
+	RepositoryHooks            string `json:"repository_hooks,omitempty"`
+	RepositoryProjects         string `json:"repository_projects,omitempty"`
+	SecretScanningAlerts       string `json:"secret_scanning_alerts,omitempty"`
+	Secrets                    string `json:"secrets,omitempty"`
+	SecurityEvents             string `json:"security_events,omitempty"`
+	SingleFile                 string `json:"single_file,omitempty"`
+	Statuses                   string `json:"statuses,omitempty"`
+	VulnerabilityAlerts        string `json:"vulnerability_alerts,omitempty"`
+	Workflows                  string `json:"workflows,omitempty"`
+
+	// Organization permissions.
+	Members                                 string `json:"members,omitempty"`
+	OrganizationAdministration              string `json:"organization_administration,omitempty"`
+	OrganizationAnnouncementBanners         string `json:"organization_announcement_banners,omitempty"`
+	OrganizationCopilotSeatManagement       string `json:"organization_copilot_seat_management,omitempty"`
+	OrganizationCustomOrgRoles              string `json:"organization_custom_org_roles,omitempty"`
+	OrganizationCustomProperties            string `json:"organization_custom_properties,omitempty"`
+	OrganizationCustomRoles                 string `json:"organization_custom_roles,omitempty"`
+	OrganizationEvents                      string `json:"organization_events,omitempty"`
+	OrganizationHooks                       string `json:"organization_hooks,omitempty"`
+	OrganizationPackages                    string `json:"organization_packages,omitempty"`
+	OrganizationPersonalAccessTokenRequests string `json:"organization_personal_access_token_requests,omitempty"`
+	OrganizationPersonalAccessTokens        string `json:"organization_personal_access_tokens,omitempty"`
+	OrganizationPlan                        string `json:"organization_plan,omitempty"`
+	OrganizationProjects                    string `json:"organization_projects,omitempty"`
+	OrganizationSecrets                     string `json:"organization_secrets,omitempty"`
+	OrganizationSelfHostedRunners           string `json:"organization_self_hosted_runners,omitempty"`
+	OrganizationUserBlocking                string `json:"organization_user_blocking,omitempty"`
+	TeamDiscussions                         string `json:"team_discussions,omitempty"`
+
+	// Account permissions.
+	EmailAddresses    string `json:"email_addresses,omitempty"`
+	Followers         string `json:"followers,omitempty"`
+	GitSSHKeys        string `json:"git_ssh_keys,omitempty"`
+	GPGKeys           string `json:"gpg_keys,omitempty"`
+	InteractionLimits string `json:"interaction_limits,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	Starring          string `json:"starring,omitempty"`
+
+	// Other holds permissions without a dedicated field.
+	Other map[string]string `json:"-"`
+}
+
+// installationPermissionFields maps each permission name to the index of its
+// field in InstallationPermissions.
+var installationPermissionFields = func() map[string]int {
+	fields := make(map[string]int)
+	t := reflect.TypeOf(InstallationPermissions{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	return fields
+}()
+
+// Get returns the access level granted for the named permission, or an empty
+// string when it was not granted.
+func (p InstallationPermissions) Get(name string) string {
+	if i, ok := installationPermissionFields[name]; ok {
+		return reflect.ValueOf(p).Field(i).String()
+	}
+	return p.Other[name]
+}
+
+// Map returns every granted permission keyed by name.
+func (p InstallationPermissions) Map() map[string]string {
+	m := make(map[string]string, len(p.Other))
+	v := reflect.ValueOf(p)
+	for name, i := range installationPermissionFields {
+		if level := v.Field(i).String(); level != "" {
+			m[name] = level
+		}
+	}
+	for name, level := range p.Other {
+		m[name] = level
+	}
+	return m
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Permissions without a dedicated field are collected in Other.
+func (p *InstallationPermissions) UnmarshalJSON(data []byte) error {
+	var all map[string]string
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	*p = InstallationPermissions{}
+	v := reflect.ValueOf(p).Elem()
+	for name, level := range all {
+		if i, ok := installationPermissionFields[name]; ok {
+			v.Field(i).SetString(level)
+			continue
+		}
+		if p.Other == nil {
+			p.Other = make(map[string]string)
+		}
+		p.Other[name] = level
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p InstallationPermissions) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Map())
 }
 
 // InstallationRepositoriesPayload represents the webhook payload sent for installation_repositories events.
 type InstallationRepositoriesPayload struct {
 	WebhookPayload
-	Installation struct {
-		ID                  int64     `json:"id"`
-		NodeID              string    `json:"node_id"`
-		AppID               int64     `json:"app_id"`
-		AppSlug             string    `json:"app_slug"`
-		TargetID            int64     `json:"target_id"`
-		TargetType          string    `json:"target_type"`
-		RepositorySelection string    `json:"repository_selection"`
-		Account             User      `json:"account"`
-		AccessTokensURL     string    `json:"access_tokens_url"`
-		RepositoriesURL     string    `json:"repositories_url"`
-		HTMLURL             string    `json:"html_url"`
-		CreatedAt           Timestamp `json:"created_at"`
-		UpdatedAt           Timestamp `json:"updated_at"`
-		Events              []string  `json:"events"`
-		Permissions         struct {
-			Issues       string `json:"issues"`
-			Contents     string `json:"contents"`
-			Metadata     string `json:"metadata"`
-			SingleFile   string `json:"single_file"`
-			PullRequests string `json:"pull_requests"`
-		} `json:"permissions"`
-	} `json:"installation"`
+	Installation        Installation `json:"installation"`
 	RepositoriesAdded   []Repository `json:"repositories_added"`
 	RepositoriesRemoved []Repository `json:"repositories_removed"`
 	RepositorySelection string       `json:"repository_selection"`
+	// Requester is the user who requested the change, when it needed approval.
+	Requester *User `json:"requester,omitempty"`
+}
+
+// InstallationTargetPayload represents the webhook payload sent for
+// installation_target events, when the account an app is installed on is
+// renamed. The only action is renamed.
+type InstallationTargetPayload struct {
+	WebhookPayload
+	Account    User   `json:"account"`
+	TargetType string `json:"target_type"`
+	Changes    struct {
+		Login *ChangedFromString `json:"login,omitempty"`
+		Slug  *ChangedFromString `json:"slug,omitempty"`
+	} `json:"changes"`
+}
+
+// IssueDependenciesPayload represents the webhook payload sent for
+// issue_dependencies events. The action is one of blocked_by_added,
+// blocked_by_removed, blocking_added or blocking_removed; BlockedIssue is the
+// issue that cannot proceed and BlockingIssue the one it waits on.
+type IssueDependenciesPayload struct {
+	WebhookPayload
+	BlockedIssueID    int64       `json:"blocked_issue_id"`
+	BlockedIssue      *Issue      `json:"blocked_issue"`
+	BlockedIssueRepo  *Repository `json:"blocked_issue_repo,omitempty"`
+	BlockingIssueID   int64       `json:"blocking_issue_id"`
+	BlockingIssue     *Issue      `json:"blocking_issue"`
+	BlockingIssueRepo *Repository `json:"blocking_issue_repo,omitempty"`
 }
 
 // LabelPayload represents the webhook payload sent for label events.
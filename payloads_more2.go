@@ -2,8 +2,76 @@ package github
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 )
 
+// MergeGroupPayload represents the webhook payload sent for merge_group events.
+type MergeGroupPayload struct {
+	WebhookPayload
+	MergeGroup MergeGroup `json:"merge_group"`
+	// Reason explains why a merge group was destroyed: merged, invalidated or
+	// dequeued. It is only present for the destroyed action.
+	Reason string `json:"reason,omitempty"`
+}
+
+// MergeGroup represents a group of pull requests being tested together in a merge queue.
+type MergeGroup struct {
+	HeadSHA    string `json:"head_sha"`
+	HeadRef    string `json:"head_ref"`
+	BaseSHA    string `json:"base_sha"`
+	BaseRef    string `json:"base_ref"`
+	HeadCommit Commit `json:"head_commit"`
+}
+
+// QueueRef parses the merge group's head ref.
+func (m *MergeGroup) QueueRef() (MergeQueueRef, bool) {
+	return ParseMergeQueueRef(m.HeadRef)
+}
+
+// mergeQueueBranchPrefix is the branch namespace GitHub uses for merge queue entries.
+const mergeQueueBranchPrefix = "gh-readonly-queue/"
+
+// MergeQueueRef describes a temporary merge queue branch such as
+// gh-readonly-queue/main/pr-42-0123abcd. Check suites and check runs for merge
+// queue entries carry no explicit merge_group object, only this branch name.
+type MergeQueueRef struct {
+	// BaseBranch is the branch the queue merges into.
+	BaseBranch string
+	// PullRequestNumber is the number of the pull request at the head of the group.
+	PullRequestNumber int
+	// HeadSHA is the head commit of that pull request.
+	HeadSHA string
+}
+
+// ParseMergeQueueRef parses a merge queue branch name, with or without the
+// refs/heads/ prefix. It reports false if ref is not a merge queue branch.
+func ParseMergeQueueRef(ref string) (MergeQueueRef, bool) {
+	ref = strings.TrimPrefix(ref, "refs/heads/")
+	if !strings.HasPrefix(ref, mergeQueueBranchPrefix) {
+		return MergeQueueRef{}, false
+	}
+	ref = strings.TrimPrefix(ref, mergeQueueBranchPrefix)
+
+	// The base branch may itself contain slashes, so split on the last "/pr-"
+	i := strings.LastIndex(ref, "/pr-")
+	if i <= 0 {
+		return MergeQueueRef{}, false
+	}
+	base, entry := ref[:i], ref[i+len("/pr-"):]
+
+	number, sha, ok := strings.Cut(entry, "-")
+	if !ok {
+		return MergeQueueRef{}, false
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return MergeQueueRef{}, false
+	}
+
+	return MergeQueueRef{BaseBranch: base, PullRequestNumber: n, HeadSHA: sha}, true
+}
+
 // MilestonePayload represents the webhook payload sent for milestone events.
 type MilestonePayload struct {
 	WebhookPayload
@@ -97,6 +165,55 @@ type PageBuildPayload struct {
 	} `json:"build"`
 }
 
+// PersonalAccessTokenRequestPayload represents the webhook payload sent for
+// personal_access_token_request events. The action is created, approved, denied
+// or cancelled.
+type PersonalAccessTokenRequestPayload struct {
+	WebhookPayload
+	PersonalAccessTokenRequest PersonalAccessTokenRequest `json:"personal_access_token_request"`
+}
+
+// PersonalAccessTokenRequest represents a request for a fine-grained personal
+// access token to access resources owned by an organization.
+type PersonalAccessTokenRequest struct {
+	ID    int64 `json:"id"`
+	Owner User  `json:"owner"`
+	// PermissionsAdded are permissions the token did not have before.
+	PermissionsAdded PersonalAccessTokenPermissions `json:"permissions_added"`
+	// PermissionsUpgraded are existing permissions raised to a higher access level.
+	PermissionsUpgraded PersonalAccessTokenPermissions `json:"permissions_upgraded"`
+	// PermissionsResult is the full set of permissions the token would have if approved.
+	PermissionsResult PersonalAccessTokenPermissions `json:"permissions_result"`
+	// RepositorySelection is one of none, all or subset.
+	RepositorySelection string `json:"repository_selection"`
+	// RepositoryCount and Repositories are only set when RepositorySelection is subset.
+	RepositoryCount *int                            `json:"repository_count"`
+	Repositories    []PersonalAccessTokenRepository `json:"repositories"`
+	CreatedAt       Timestamp                       `json:"created_at"`
+	TokenID         int64                           `json:"token_id"`
+	TokenName       string                          `json:"token_name,omitempty"`
+	TokenExpired    bool                            `json:"token_expired"`
+	TokenExpiresAt  *Timestamp                      `json:"token_expires_at"`
+	TokenLastUsedAt *Timestamp                      `json:"token_last_used_at"`
+}
+
+// PersonalAccessTokenPermissions maps permission names to access levels, such
+// as "read" or "write", for each permission scope.
+type PersonalAccessTokenPermissions struct {
+	Organization map[string]string `json:"organization,omitempty"`
+	Repository   map[string]string `json:"repository,omitempty"`
+	Other        map[string]string `json:"other,omitempty"`
+}
+
+// PersonalAccessTokenRepository represents a repository a token requests access to.
+type PersonalAccessTokenRepository struct {
+	ID       int64  `json:"id"`
+	NodeID   string `json:"node_id"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	Private  bool   `json:"private"`
+}
+
 // ProjectPayload represents the webhook payload sent for project events.
 type ProjectPayload struct {
 	WebhookPayload
@@ -158,6 +275,216 @@ type ProjectColumnPayload struct {
 	} `json:"changes,omitempty"`
 }
 
+// ProjectsV2Payload represents the webhook payload sent for projects_v2 events.
+type ProjectsV2Payload struct {
+	WebhookPayload
+	ProjectsV2 ProjectsV2 `json:"projects_v2"`
+	Changes    struct {
+		Title            *ChangedFromToString `json:"title,omitempty"`
+		Description      *ChangedFromToString `json:"description,omitempty"`
+		ShortDescription *ChangedFromToString `json:"short_description,omitempty"`
+		Readme           *ChangedFromToString `json:"readme,omitempty"`
+		Public           *ChangedFromToBool   `json:"public,omitempty"`
+	} `json:"changes,omitempty"`
+}
+
+// ProjectsV2 represents a GitHub project (the successor to classic projects).
+type ProjectsV2 struct {
+	ID               int64      `json:"id"`
+	NodeID           string     `json:"node_id"`
+	Number           int        `json:"number"`
+	Title            string     `json:"title"`
+	Description      *string    `json:"description"`
+	ShortDescription *string    `json:"short_description"`
+	Public           bool       `json:"public"`
+	Owner            User       `json:"owner"`
+	Creator          User       `json:"creator"`
+	CreatedAt        Timestamp  `json:"created_at"`
+	UpdatedAt        Timestamp  `json:"updated_at"`
+	ClosedAt         *Timestamp `json:"closed_at"`
+	DeletedAt        *Timestamp `json:"deleted_at,omitempty"`
+	DeletedBy        *User      `json:"deleted_by,omitempty"`
+}
+
+// ProjectsV2ItemPayload represents the webhook payload sent for projects_v2_item events.
+type ProjectsV2ItemPayload struct {
+	WebhookPayload
+	ProjectsV2Item ProjectsV2Item `json:"projects_v2_item"`
+	Changes        struct {
+		FieldValue                   *ProjectsV2FieldValueChange `json:"field_value,omitempty"`
+		Body                         *ChangedFromToString        `json:"body,omitempty"`
+		ArchivedAt                   *ChangedFromToTimestamp     `json:"archived_at,omitempty"`
+		ContentType                  *ChangedFromToString        `json:"content_type,omitempty"`
+		PreviousProjectsV2ItemNodeID *ChangedFromToString        `json:"previous_projects_v2_item_node_id,omitempty"`
+	} `json:"changes,omitempty"`
+}
+
+// ProjectsV2Item represents an item in a project. ContentType is one of
+// "Issue", "PullRequest" or "DraftIssue".
+type ProjectsV2Item struct {
+	ID            int64      `json:"id"`
+	NodeID        string     `json:"node_id"`
+	ProjectNodeID string     `json:"project_node_id"`
+	ContentNodeID string     `json:"content_node_id"`
+	ContentType   string     `json:"content_type"`
+	Creator       *User      `json:"creator,omitempty"`
+	CreatedAt     Timestamp  `json:"created_at"`
+	UpdatedAt     Timestamp  `json:"updated_at"`
+	ArchivedAt    *Timestamp `json:"archived_at"`
+}
+
+// ProjectsV2FieldValueChange describes a field value edited on a project item.
+// FieldType determines the concrete type of From and To:
+//
+//	single_select  *ProjectsV2SingleSelectOption
+//	iteration      *ProjectsV2IterationOption
+//	date           ProjectsV2DateValue
+//	number         ProjectsV2NumberValue
+//	text           ProjectsV2TextValue
+//
+// From and To are nil when the field was unset, for field types this package
+// does not decode, and for values whose shape does not match their field type;
+// the raw JSON is still available in RawFrom and RawTo.
+type ProjectsV2FieldValueChange struct {
+	FieldNodeID   string               `json:"field_node_id"`
+	FieldType     string               `json:"field_type"`
+	FieldName     string               `json:"field_name,omitempty"`
+	ProjectNumber int                  `json:"project_number,omitempty"`
+	From          ProjectsV2FieldValue `json:"from,omitempty"`
+	To            ProjectsV2FieldValue `json:"to,omitempty"`
+	RawFrom       json.RawMessage      `json:"-"`
+	RawTo         json.RawMessage      `json:"-"`
+}
+
+// ProjectsV2FieldValue is implemented by each concrete project field value type.
+type ProjectsV2FieldValue interface {
+	projectsV2FieldValue()
+}
+
+// ProjectsV2SingleSelectOption represents an option of a single select field.
+type ProjectsV2SingleSelectOption struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProjectsV2IterationOption represents an iteration of an iteration field.
+// StartDate is formatted as YYYY-MM-DD and Duration is in days.
+type ProjectsV2IterationOption struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"start_date"`
+	Duration  int    `json:"duration"`
+}
+
+// ProjectsV2DateValue is the value of a date field.
+type ProjectsV2DateValue string
+
+// ProjectsV2NumberValue is the value of a number field.
+type ProjectsV2NumberValue float64
+
+// ProjectsV2TextValue is the value of a text field.
+type ProjectsV2TextValue string
+
+func (*ProjectsV2SingleSelectOption) projectsV2FieldValue() {}
+func (*ProjectsV2IterationOption) projectsV2FieldValue()    {}
+func (ProjectsV2DateValue) projectsV2FieldValue()           {}
+func (ProjectsV2NumberValue) projectsV2FieldValue()         {}
+func (ProjectsV2TextValue) projectsV2FieldValue()           {}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes From and To into the concrete type that matches FieldType.
+func (c *ProjectsV2FieldValueChange) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		FieldNodeID   string          `json:"field_node_id"`
+		FieldType     string          `json:"field_type"`
+		FieldName     string          `json:"field_name"`
+		ProjectNumber int             `json:"project_number"`
+		From          json.RawMessage `json:"from"`
+		To            json.RawMessage `json:"to"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	c.FieldNodeID = raw.FieldNodeID
+	c.FieldType = raw.FieldType
+	c.FieldName = raw.FieldName
+	c.ProjectNumber = raw.ProjectNumber
+	c.RawFrom = raw.From
+	c.RawTo = raw.To
+
+	c.From = decodeProjectsV2FieldValue(raw.FieldType, raw.From)
+	c.To = decodeProjectsV2FieldValue(raw.FieldType, raw.To)
+	return nil
+}
+
+// decodeProjectsV2FieldValue decodes a single field value of the given field
+// type. It returns nil rather than an error for a value of an unexpected shape,
+// so a change GitHub reports differently does not fail the whole payload.
+func decodeProjectsV2FieldValue(fieldType string, data json.RawMessage) ProjectsV2FieldValue {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	switch fieldType {
+	case "single_select":
+		v := new(ProjectsV2SingleSelectOption)
+		if json.Unmarshal(data, v) == nil {
+			return v
+		}
+	case "iteration":
+		v := new(ProjectsV2IterationOption)
+		if json.Unmarshal(data, v) == nil {
+			return v
+		}
+	case "date":
+		var v ProjectsV2DateValue
+		if json.Unmarshal(data, &v) == nil {
+			return v
+		}
+	case "number":
+		var v ProjectsV2NumberValue
+		if json.Unmarshal(data, &v) == nil {
+			return v
+		}
+	case "text":
+		var v ProjectsV2TextValue
+		if json.Unmarshal(data, &v) == nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// ProjectsV2StatusUpdatePayload represents the webhook payload sent for projects_v2_status_update events.
+type ProjectsV2StatusUpdatePayload struct {
+	WebhookPayload
+	ProjectsV2StatusUpdate ProjectsV2StatusUpdate `json:"projects_v2_status_update"`
+	Changes                struct {
+		Body       *ChangedFromToString `json:"body,omitempty"`
+		Status     *ChangedFromToString `json:"status,omitempty"`
+		StartDate  *ChangedFromToString `json:"start_date,omitempty"`
+		TargetDate *ChangedFromToString `json:"target_date,omitempty"`
+	} `json:"changes,omitempty"`
+}
+
+// ProjectsV2StatusUpdate represents a status update posted to a project.
+// Status is one of INACTIVE, ON_TRACK, AT_RISK, OFF_TRACK or COMPLETE.
+type ProjectsV2StatusUpdate struct {
+	ID            int64     `json:"id"`
+	NodeID        string    `json:"node_id"`
+	ProjectNodeID string    `json:"project_node_id"`
+	Creator       *User     `json:"creator,omitempty"`
+	Status        *string   `json:"status"`
+	StartDate     *string   `json:"start_date,omitempty"`
+	TargetDate    *string   `json:"target_date,omitempty"`
+	Body          *string   `json:"body"`
+	CreatedAt     Timestamp `json:"created_at"`
+	UpdatedAt     Timestamp `json:"updated_at"`
+}
+
 // PublicPayload represents the webhook payload sent for public events.
 type PublicPayload struct {
 	WebhookPayload
@@ -187,31 +514,52 @@ type PullRequestReviewPayload struct {
 // PullRequestReviewCommentPayload represents the webhook payload sent for pull_request_review_comment events.
 type PullRequestReviewCommentPayload struct {
 	WebhookPayload
-	Comment struct {
-		ID                  int64     `json:"id"`
-		NodeID              string    `json:"node_id"`
-		Path                string    `json:"path"`
-		Position            *int      `json:"position"`
-		OriginalPosition    int       `json:"original_position"`
-		CommitID            string    `json:"commit_id"`
-		OriginalCommitID    string    `json:"original_commit_id"`
-		User                User      `json:"user"`
-		Body                string    `json:"body"`
-		CreatedAt           Timestamp `json:"created_at"`
-		UpdatedAt           Timestamp `json:"updated_at"`
-		HTMLURL             string    `json:"html_url"`
-		PullRequestURL      string    `json:"pull_request_url"`
-		AuthorAssociation   string    `json:"author_association"`
-		DiffHunk            string    `json:"diff_hunk"`
-		PullRequestReviewID int64     `json:"pull_request_review_id"`
-		InReplyToID         *int64    `json:"in_reply_to_id"`
-	} `json:"comment"`
-	PullRequest PullRequest `json:"pull_request"`
+	Comment     PullRequestReviewComment `json:"comment"`
+	PullRequest PullRequest              `json:"pull_request"`
 	Changes     struct {
 		Body *ChangedFrom `json:"body,omitempty"`
 	} `json:"changes,omitempty"`
 }
 
+// PullRequestReviewComment represents a comment on the diff of a pull request.
+type PullRequestReviewComment struct {
+	ID                  int64     `json:"id"`
+	NodeID              string    `json:"node_id"`
+	Path                string    `json:"path"`
+	Position            *int      `json:"position"`
+	OriginalPosition    int       `json:"original_position"`
+	Line                *int      `json:"line"`
+	OriginalLine        *int      `json:"original_line"`
+	Side                string    `json:"side,omitempty"`
+	StartLine           *int      `json:"start_line"`
+	OriginalStartLine   *int      `json:"original_start_line"`
+	StartSide           *string   `json:"start_side"`
+	SubjectType         string    `json:"subject_type,omitempty"`
+	CommitID            string    `json:"commit_id"`
+	OriginalCommitID    string    `json:"original_commit_id"`
+	User                User      `json:"user"`
+	Body                string    `json:"body"`
+	CreatedAt           Timestamp `json:"created_at"`
+	UpdatedAt           Timestamp `json:"updated_at"`
+	HTMLURL             string    `json:"html_url"`
+	PullRequestURL      string    `json:"pull_request_url"`
+	AuthorAssociation   string    `json:"author_association"`
+	DiffHunk            string    `json:"diff_hunk"`
+	PullRequestReviewID int64     `json:"pull_request_review_id"`
+	InReplyToID         *int64    `json:"in_reply_to_id"`
+}
+
+// PullRequestReviewThreadPayload represents the webhook payload sent for
+// pull_request_review_thread events. The action is resolved or unresolved.
+type PullRequestReviewThreadPayload struct {
+	WebhookPayload
+	Thread struct {
+		NodeID   string                     `json:"node_id"`
+		Comments []PullRequestReviewComment `json:"comments"`
+	} `json:"thread"`
+	PullRequest PullRequest `json:"pull_request"`
+}
+
 // ReleasePayload represents the webhook payload sent for release events.
 type ReleasePayload struct {
 	WebhookPayload
@@ -250,13 +598,110 @@ type ReleasePayload struct {
 }
 
 // RepositoryPayload represents the webhook payload sent for repository events.
+// The action is one of created, deleted, archived, unarchived, edited,
+// renamed, transferred, publicized or privatized. Changes is only populated
+// for edited, renamed and transferred; for publicized and privatized the new
+// visibility is in Repository.Visibility.
 type RepositoryPayload struct {
 	WebhookPayload
-	Changes struct {
-		DefaultBranch *ChangedFrom `json:"default_branch,omitempty"`
-		Description   *ChangedFrom `json:"description,omitempty"`
-		Homepage      *ChangedFrom `json:"homepage,omitempty"`
-	} `json:"changes,omitempty"`
+	Changes RepositoryChanges `json:"changes,omitempty"`
+}
+
+// RepositoryChanges describes the modifications reported by a repository event.
+type RepositoryChanges struct {
+	// DefaultBranch, Description, Homepage and Topics are set by the edited action.
+	DefaultBranch *ChangedFromString         `json:"default_branch,omitempty"`
+	Description   *ChangedFromNullableString `json:"description,omitempty"`
+	Homepage      *ChangedFromNullableString `json:"homepage,omitempty"`
+	Topics        *ChangedFromStrings        `json:"topics,omitempty"`
+	// Repository holds the previous name for the renamed action.
+	Repository *struct {
+		Name *ChangedFromString `json:"name,omitempty"`
+	} `json:"repository,omitempty"`
+	// Owner holds the previous owner for the transferred action. Exactly one
+	// of User and Organization is set.
+	Owner *struct {
+		From struct {
+			User         *User         `json:"user,omitempty"`
+			Organization *Organization `json:"organization,omitempty"`
+		} `json:"from"`
+	} `json:"owner,omitempty"`
+}
+
+// PreviousName returns the repository's name before a renamed action.
+func (c *RepositoryChanges) PreviousName() (string, bool) {
+	if c.Repository == nil || c.Repository.Name == nil {
+		return "", false
+	}
+	return c.Repository.Name.From, true
+}
+
+// PreviousOwner returns the login of the repository's owner before a
+// transferred action.
+func (c *RepositoryChanges) PreviousOwner() (string, bool) {
+	if c.Owner == nil {
+		return "", false
+	}
+	if u := c.Owner.From.User; u != nil {
+		return u.Login, true
+	}
+	if o := c.Owner.From.Organization; o != nil {
+		return o.Login, true
+	}
+	return "", false
+}
+
+// RepositoryAdvisoryPayload represents the webhook payload sent for
+// repository_advisory events. The action is published or reported.
+type RepositoryAdvisoryPayload struct {
+	WebhookPayload
+	RepositoryAdvisory RepositoryAdvisory `json:"repository_advisory"`
+}
+
+// RepositoryAdvisory represents a security advisory published or privately
+// reported on a repository. State is one of triage, draft, published, closed
+// or withdrawn.
+type RepositoryAdvisory struct {
+	GHSAID          string                            `json:"ghsa_id"`
+	CVEID           *string                           `json:"cve_id"`
+	URL             string                            `json:"url"`
+	HTMLURL         string                            `json:"html_url"`
+	Summary         string                            `json:"summary"`
+	Description     *string                           `json:"description"`
+	Severity        *string                           `json:"severity"`
+	State           string                            `json:"state"`
+	Author          *User                             `json:"author"`
+	Publisher       *User                             `json:"publisher"`
+	Identifiers     []SecurityAdvisoryIdentifier      `json:"identifiers"`
+	CreatedAt       *Timestamp                        `json:"created_at"`
+	UpdatedAt       *Timestamp                        `json:"updated_at"`
+	PublishedAt     *Timestamp                        `json:"published_at"`
+	ClosedAt        *Timestamp                        `json:"closed_at"`
+	WithdrawnAt     *Timestamp                        `json:"withdrawn_at"`
+	Vulnerabilities []RepositoryAdvisoryVulnerability `json:"vulnerabilities"`
+	CVSS            *SecurityAdvisoryCVSS             `json:"cvss"`
+	CWES            []SecurityAdvisoryCWE             `json:"cwes"`
+	CWEIDs          []string                          `json:"cwe_ids"`
+	// Submission is set for advisories reported privately by a security researcher.
+	Submission *struct {
+		Accepted bool `json:"accepted"`
+	} `json:"submission"`
+	CreditsDetailed []struct {
+		User  User   `json:"user"`
+		Type  string `json:"type"`
+		State string `json:"state"`
+	} `json:"credits_detailed"`
+	CollaboratingUsers []User      `json:"collaborating_users"`
+	CollaboratingTeams []Team      `json:"collaborating_teams"`
+	PrivateFork        *Repository `json:"private_fork"`
+}
+
+// RepositoryAdvisoryVulnerability represents a package affected by a repository advisory.
+type RepositoryAdvisoryVulnerability struct {
+	Package                *SecurityAdvisoryPackage `json:"package"`
+	VulnerableVersionRange *string                  `json:"vulnerable_version_range"`
+	PatchedVersions        *string                  `json:"patched_versions"`
+	VulnerableFunctions    []string                 `json:"vulnerable_functions"`
 }
 
 // RepositoryDispatchPayload represents the webhook payload sent for repository_dispatch events.
@@ -272,6 +717,309 @@ type RepositoryImportPayload struct {
 	Status string `json:"status"`
 }
 
+// RepositoryRulesetPayload represents the webhook payload sent for repository_ruleset events.
+type RepositoryRulesetPayload struct {
+	WebhookPayload
+	RepositoryRuleset RepositoryRuleset `json:"repository_ruleset"`
+	// Changes describes what an edited action modified.
+	Changes *RepositoryRulesetChanges `json:"changes,omitempty"`
+}
+
+// RepositoryRuleset represents a set of rules applied to branches, tags or
+// pushes in a repository, organization or enterprise.
+type RepositoryRuleset struct {
+	ID                   int64                  `json:"id"`
+	NodeID               string                 `json:"node_id"`
+	Name                 string                 `json:"name"`
+	Target               string                 `json:"target"`
+	SourceType           string                 `json:"source_type"`
+	Source               string                 `json:"source"`
+	Enforcement          string                 `json:"enforcement"`
+	BypassActors         []RulesetBypassActor   `json:"bypass_actors"`
+	CurrentUserCanBypass string                 `json:"current_user_can_bypass,omitempty"`
+	Conditions           *RulesetConditions     `json:"conditions"`
+	Rules                []RepositoryRule       `json:"rules"`
+	CreatedAt            *Timestamp             `json:"created_at"`
+	UpdatedAt            *Timestamp             `json:"updated_at"`
+	Links                map[string]RulesetLink `json:"_links,omitempty"`
+}
+
+// RulesetLink represents a hypermedia link on a ruleset.
+type RulesetLink struct {
+	HRef string `json:"href"`
+}
+
+// RulesetBypassActor represents an actor that may bypass a ruleset. ActorType
+// is one of Integration, OrganizationAdmin, RepositoryRole, Team or DeployKey,
+// and BypassMode is either "always" or "pull_request".
+type RulesetBypassActor struct {
+	ActorID    *int64 `json:"actor_id"`
+	ActorType  string `json:"actor_type"`
+	BypassMode string `json:"bypass_mode"`
+}
+
+// RulesetConditions represents the conditions that select which refs and
+// repositories a ruleset applies to.
+type RulesetConditions struct {
+	RefName            *RulesetRefNameCondition            `json:"ref_name,omitempty"`
+	RepositoryName     *RulesetRepositoryNameCondition     `json:"repository_name,omitempty"`
+	RepositoryID       *RulesetRepositoryIDCondition       `json:"repository_id,omitempty"`
+	RepositoryProperty *RulesetRepositoryPropertyCondition `json:"repository_property,omitempty"`
+}
+
+// RulesetRefNameCondition selects refs by name pattern.
+type RulesetRefNameCondition struct {
+	Include []string `json:"include"`
+	Exclude []string `json:"exclude"`
+}
+
+// RulesetRepositoryNameCondition selects repositories by name pattern.
+type RulesetRepositoryNameCondition struct {
+	Include   []string `json:"include"`
+	Exclude   []string `json:"exclude"`
+	Protected bool     `json:"protected"`
+}
+
+// RulesetRepositoryIDCondition selects repositories by ID.
+type RulesetRepositoryIDCondition struct {
+	RepositoryIDs []int64 `json:"repository_ids"`
+}
+
+// RulesetRepositoryPropertyCondition selects repositories by custom property values.
+type RulesetRepositoryPropertyCondition struct {
+	Include []RulesetPropertyTarget `json:"include"`
+	Exclude []RulesetPropertyTarget `json:"exclude"`
+}
+
+// RulesetPropertyTarget matches repositories whose custom property has one of the given values.
+type RulesetPropertyTarget struct {
+	Name           string   `json:"name"`
+	PropertyValues []string `json:"property_values"`
+	Source         string   `json:"source,omitempty"`
+}
+
+// RepositoryRule represents a single rule in a ruleset. Type identifies the
+// rule and determines the concrete type of Parameters:
+//
+//	update                                 *UpdateRuleParameters
+//	merge_queue                            *MergeQueueRuleParameters
+//	required_deployments                   *RequiredDeploymentsRuleParameters
+//	pull_request                           *PullRequestRuleParameters
+//	required_status_checks                 *RequiredStatusChecksRuleParameters
+//	commit_message_pattern,
+//	commit_author_email_pattern,
+//	committer_email_pattern,
+//	branch_name_pattern, tag_name_pattern  *PatternRuleParameters
+//	file_path_restriction                  *FilePathRestrictionRuleParameters
+//	max_file_path_length                   *MaxFilePathLengthRuleParameters
+//	file_extension_restriction             *FileExtensionRestrictionRuleParameters
+//	max_file_size                          *MaxFileSizeRuleParameters
+//	workflows                              *WorkflowsRuleParameters
+//	code_scanning                          *CodeScanningRuleParameters
+//
+// Rules without parameters (creation, deletion, required_linear_history,
+// required_signatures, non_fast_forward) and rule types this package does not
+// know about have nil Parameters; the raw JSON is still available in
+// RawParameters.
+type RepositoryRule struct {
+	Type          string                   `json:"type"`
+	Parameters    RepositoryRuleParameters `json:"parameters,omitempty"`
+	RawParameters json.RawMessage          `json:"-"`
+}
+
+// RepositoryRuleParameters is implemented by each concrete rule parameter type.
+type RepositoryRuleParameters interface {
+	repositoryRuleParameters()
+}
+
+// UpdateRuleParameters configures the update rule.
+type UpdateRuleParameters struct {
+	UpdateAllowsFetchAndMerge bool `json:"update_allows_fetch_and_merge"`
+}
+
+// MergeQueueRuleParameters configures the merge_queue rule.
+type MergeQueueRuleParameters struct {
+	CheckResponseTimeoutMinutes  int    `json:"check_response_timeout_minutes"`
+	GroupingStrategy             string `json:"grouping_strategy"`
+	MaxEntriesToBuild            int    `json:"max_entries_to_build"`
+	MaxEntriesToMerge            int    `json:"max_entries_to_merge"`
+	MergeMethod                  string `json:"merge_method"`
+	MinEntriesToMerge            int    `json:"min_entries_to_merge"`
+	MinEntriesToMergeWaitMinutes int    `json:"min_entries_to_merge_wait_minutes"`
+}
+
+// RequiredDeploymentsRuleParameters configures the required_deployments rule.
+type RequiredDeploymentsRuleParameters struct {
+	RequiredDeploymentEnvironments []string `json:"required_deployment_environments"`
+}
+
+// PullRequestRuleParameters configures the pull_request rule.
+type PullRequestRuleParameters struct {
+	AllowedMergeMethods            []string `json:"allowed_merge_methods,omitempty"`
+	DismissStaleReviewsOnPush      bool     `json:"dismiss_stale_reviews_on_push"`
+	RequireCodeOwnerReview         bool     `json:"require_code_owner_review"`
+	RequireLastPushApproval        bool     `json:"require_last_push_approval"`
+	RequiredApprovingReviewCount   int      `json:"required_approving_review_count"`
+	RequiredReviewThreadResolution bool     `json:"required_review_thread_resolution"`
+}
+
+// RequiredStatusChecksRuleParameters configures the required_status_checks rule.
+type RequiredStatusChecksRuleParameters struct {
+	DoNotEnforceOnCreate             bool `json:"do_not_enforce_on_create,omitempty"`
+	StrictRequiredStatusChecksPolicy bool `json:"strict_required_status_checks_policy"`
+	RequiredStatusChecks             []struct {
+		Context       string `json:"context"`
+		IntegrationID *int64 `json:"integration_id,omitempty"`
+	} `json:"required_status_checks"`
+}
+
+// PatternRuleParameters configures the commit message, author email, committer
+// email, branch name and tag name pattern rules. Operator is one of
+// starts_with, ends_with, contains or regex.
+type PatternRuleParameters struct {
+	Name     string `json:"name,omitempty"`
+	Negate   bool   `json:"negate"`
+	Operator string `json:"operator"`
+	Pattern  string `json:"pattern"`
+}
+
+// FilePathRestrictionRuleParameters configures the file_path_restriction rule.
+type FilePathRestrictionRuleParameters struct {
+	RestrictedFilePaths []string `json:"restricted_file_paths"`
+}
+
+// MaxFilePathLengthRuleParameters configures the max_file_path_length rule.
+type MaxFilePathLengthRuleParameters struct {
+	MaxFilePathLength int `json:"max_file_path_length"`
+}
+
+// FileExtensionRestrictionRuleParameters configures the file_extension_restriction rule.
+type FileExtensionRestrictionRuleParameters struct {
+	RestrictedFileExtensions []string `json:"restricted_file_extensions"`
+}
+
+// MaxFileSizeRuleParameters configures the max_file_size rule. The size is in megabytes.
+type MaxFileSizeRuleParameters struct {
+	MaxFileSize int `json:"max_file_size"`
+}
+
+// WorkflowsRuleParameters configures the workflows rule.
+type WorkflowsRuleParameters struct {
+	DoNotEnforceOnCreate bool `json:"do_not_enforce_on_create,omitempty"`
+	Workflows            []struct {
+		Path         string `json:"path"`
+		Ref          string `json:"ref,omitempty"`
+		RepositoryID int64  `json:"repository_id"`
+		SHA          string `json:"sha,omitempty"`
+	} `json:"workflows"`
+}
+
+// CodeScanningRuleParameters configures the code_scanning rule.
+type CodeScanningRuleParameters struct {
+	CodeScanningTools []struct {
+		Tool                    string `json:"tool"`
+		AlertsThreshold         string `json:"alerts_threshold"`
+		SecurityAlertsThreshold string `json:"security_alerts_threshold"`
+	} `json:"code_scanning_tools"`
+}
+
+func (*UpdateRuleParameters) repositoryRuleParameters()                   {}
+func (*MergeQueueRuleParameters) repositoryRuleParameters()               {}
+func (*RequiredDeploymentsRuleParameters) repositoryRuleParameters()      {}
+func (*PullRequestRuleParameters) repositoryRuleParameters()              {}
+func (*RequiredStatusChecksRuleParameters) repositoryRuleParameters()     {}
+func (*PatternRuleParameters) repositoryRuleParameters()                  {}
+func (*FilePathRestrictionRuleParameters) repositoryRuleParameters()      {}
+func (*MaxFilePathLengthRuleParameters) repositoryRuleParameters()        {}
+func (*FileExtensionRestrictionRuleParameters) repositoryRuleParameters() {}
+func (*MaxFileSizeRuleParameters) repositoryRuleParameters()              {}
+func (*WorkflowsRuleParameters) repositoryRuleParameters()                {}
+func (*CodeScanningRuleParameters) repositoryRuleParameters()             {}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes Parameters into the concrete type that matches Type.
+func (r *RepositoryRule) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type       string          `json:"type"`
+		Parameters json.RawMessage `json:"parameters"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	r.Type = raw.Type
+	r.RawParameters = raw.Parameters
+	r.Parameters = nil
+
+	switch raw.Type {
+	case "update":
+		r.Parameters = new(UpdateRuleParameters)
+	case "merge_queue":
+		r.Parameters = new(MergeQueueRuleParameters)
+	case "required_deployments":
+		r.Parameters = new(RequiredDeploymentsRuleParameters)
+	case "pull_request":
+		r.Parameters = new(PullRequestRuleParameters)
+	case "required_status_checks":
+		r.Parameters = new(RequiredStatusChecksRuleParameters)
+	case "commit_message_pattern", "commit_author_email_pattern", "committer_email_pattern",
+		"branch_name_pattern", "tag_name_pattern":
+		r.Parameters = new(PatternRuleParameters)
+	case "file_path_restriction":
+		r.Parameters = new(FilePathRestrictionRuleParameters)
+	case "max_file_path_length":
+		r.Parameters = new(MaxFilePathLengthRuleParameters)
+	case "file_extension_restriction":
+		r.Parameters = new(FileExtensionRestrictionRuleParameters)
+	case "max_file_size":
+		r.Parameters = new(MaxFileSizeRuleParameters)
+	case "workflows":
+		r.Parameters = new(WorkflowsRuleParameters)
+	case "code_scanning":
+		r.Parameters = new(CodeScanningRuleParameters)
+	default:
+		return nil
+	}
+
+	if len(raw.Parameters) == 0 || string(raw.Parameters) == "null" {
+		r.Parameters = nil
+		return nil
+	}
+	return json.Unmarshal(raw.Parameters, r.Parameters)
+}
+
+// RepositoryRulesetChanges describes the modifications made by an edited
+// repository_ruleset action.
+type RepositoryRulesetChanges struct {
+	Name        *ChangedFromString `json:"name,omitempty"`
+	Enforcement *ChangedFromString `json:"enforcement,omitempty"`
+	Conditions  *struct {
+		Added   []RulesetConditions `json:"added,omitempty"`
+		Deleted []RulesetConditions `json:"deleted,omitempty"`
+		Updated []struct {
+			Condition RulesetConditions `json:"condition"`
+			Changes   struct {
+				ConditionType *ChangedFromString  `json:"condition_type,omitempty"`
+				Target        *ChangedFromString  `json:"target,omitempty"`
+				Include       *ChangedFromStrings `json:"include,omitempty"`
+				Exclude       *ChangedFromStrings `json:"exclude,omitempty"`
+			} `json:"changes"`
+		} `json:"updated,omitempty"`
+	} `json:"conditions,omitempty"`
+	Rules *struct {
+		Added   []RepositoryRule `json:"added,omitempty"`
+		Deleted []RepositoryRule `json:"deleted,omitempty"`
+		Updated []struct {
+			Rule    RepositoryRule `json:"rule"`
+			Changes struct {
+				Configuration *ChangedFromString `json:"configuration,omitempty"`
+				RuleType      *ChangedFromString `json:"rule_type,omitempty"`
+				Pattern       *ChangedFromString `json:"pattern,omitempty"`
+			} `json:"changes"`
+		} `json:"updated,omitempty"`
+	} `json:"rules,omitempty"`
+}
+
 // RepositoryVulnerabilityAlertPayload represents the webhook payload sent for repository_vulnerability_alert events.
 type RepositoryVulnerabilityAlertPayload struct {
 	WebhookPayload
@@ -1,43 +1,262 @@
 package github
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
+
+// SecretScanningAlertPayload represents the webhook payload sent for secret_scanning_alert events.
+type SecretScanningAlertPayload struct {
+	WebhookPayload
+	Alert SecretScanningAlert `json:"alert"`
+	// Assignee is the user the alert was assigned to or unassigned from. It is
+	// only present for the assigned and unassigned actions.
+	Assignee *User `json:"assignee,omitempty"`
+}
+
+// SecretScanningAlert represents a secret scanning alert.
+type SecretScanningAlert struct {
+	Number                                     int        `json:"number"`
+	CreatedAt                                  Timestamp  `json:"created_at"`
+	UpdatedAt                                  *Timestamp `json:"updated_at"`
+	URL                                        string     `json:"url"`
+	HTMLURL                                    string     `json:"html_url"`
+	LocationsURL                               string     `json:"locations_url"`
+	State                                      string     `json:"state"`
+	Resolution                                 *string    `json:"resolution"`
+	ResolvedAt                                 *Timestamp `json:"resolved_at"`
+	ResolvedBy                                 *User      `json:"resolved_by"`
+	ResolutionComment                          *string    `json:"resolution_comment"`
+	SecretType                                 string     `json:"secret_type"`
+	SecretTypeDisplayName                      string     `json:"secret_type_display_name"`
+	Provider                                   *string    `json:"provider,omitempty"`
+	Secret                                     string     `json:"secret,omitempty"`
+	Validity                                   string     `json:"validity"`
+	PubliclyLeaked                             *bool      `json:"publicly_leaked"`
+	MultiRepo                                  *bool      `json:"multi_repo"`
+	IsBase64Encoded                            *bool      `json:"is_base64_encoded"`
+	AssignedTo                                 *User      `json:"assigned_to,omitempty"`
+	PushProtectionBypassed                     *bool      `json:"push_protection_bypassed"`
+	PushProtectionBypassedBy                   *User      `json:"push_protection_bypassed_by"`
+	PushProtectionBypassedAt                   *Timestamp `json:"push_protection_bypassed_at"`
+	PushProtectionBypassRequestReviewer        *User      `json:"push_protection_bypass_request_reviewer"`
+	PushProtectionBypassRequestReviewerComment *string    `json:"push_protection_bypass_request_reviewer_comment"`
+	PushProtectionBypassRequestComment         *string    `json:"push_protection_bypass_request_comment"`
+	PushProtectionBypassRequestHTMLURL         *string    `json:"push_protection_bypass_request_html_url"`
+}
+
+// SecretScanningAlertLocationPayload represents the webhook payload sent for secret_scanning_alert_location events.
+type SecretScanningAlertLocationPayload struct {
+	WebhookPayload
+	Alert    SecretScanningAlert    `json:"alert"`
+	Location SecretScanningLocation `json:"location"`
+}
+
+// SecretScanningLocation represents where a secret was found. Type identifies
+// the kind of location and determines the concrete type of Details:
+//
+//	commit, wiki_commit                          *SecretScanningCommitLocation
+//	issue_title, issue_body, issue_comment       *SecretScanningIssueLocation
+//	discussion_title, discussion_body,
+//	discussion_comment                           *SecretScanningDiscussionLocation
+//	pull_request_title, pull_request_body,
+//	pull_request_comment, pull_request_review,
+//	pull_request_review_comment                  *SecretScanningPullRequestLocation
+//
+// Details is nil when details are null or missing, and for location types this
+// package does not know about; the raw JSON is still available in RawDetails.
+type SecretScanningLocation struct {
+	Type       string                        `json:"type"`
+	Details    SecretScanningLocationDetails `json:"details"`
+	RawDetails json.RawMessage               `json:"-"`
+}
+
+// SecretScanningLocationDetails is implemented by each concrete secret scanning
+// location type.
+type SecretScanningLocationDetails interface {
+	secretScanningLocationDetails()
+}
+
+// SecretScanningCommitLocation represents a secret found in a commit or a wiki commit.
+type SecretScanningCommitLocation struct {
+	Path        string `json:"path"`
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column"`
+	EndColumn   int    `json:"end_column"`
+	BlobSHA     string `json:"blob_sha"`
+	BlobURL     string `json:"blob_url,omitempty"`
+	CommitSHA   string `json:"commit_sha"`
+	CommitURL   string `json:"commit_url"`
+	PageURL     string `json:"page_url,omitempty"`
+}
+
+// SecretScanningIssueLocation represents a secret found in an issue title, body or comment.
+type SecretScanningIssueLocation struct {
+	IssueTitleURL   string `json:"issue_title_url,omitempty"`
+	IssueBodyURL    string `json:"issue_body_url,omitempty"`
+	IssueCommentURL string `json:"issue_comment_url,omitempty"`
+}
+
+// SecretScanningDiscussionLocation represents a secret found in a discussion title, body or comment.
+type SecretScanningDiscussionLocation struct {
+	DiscussionTitleURL   string `json:"discussion_title_url,omitempty"`
+	DiscussionBodyURL    string `json:"discussion_body_url,omitempty"`
+	DiscussionCommentURL string `json:"discussion_comment_url,omitempty"`
+}
+
+// SecretScanningPullRequestLocation represents a secret found in a pull request
+// title, body, comment, review or review comment.
+type SecretScanningPullRequestLocation struct {
+	PullRequestTitleURL         string `json:"pull_request_title_url,omitempty"`
+	PullRequestBodyURL          string `json:"pull_request_body_url,omitempty"`
+	PullRequestCommentURL       string `json:"pull_request_comment_url,omitempty"`
+	PullRequestReviewURL        string `json:"pull_request_review_url,omitempty"`
+	PullRequestReviewCommentURL string `json:"pull_request_review_comment_url,omitempty"`
+}
+
+func (*SecretScanningCommitLocation) secretScanningLocationDetails()      {}
+func (*SecretScanningIssueLocation) secretScanningLocationDetails()       {}
+func (*SecretScanningDiscussionLocation) secretScanningLocationDetails()  {}
+func (*SecretScanningPullRequestLocation) secretScanningLocationDetails() {}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes Details into the concrete type that matches Type.
+func (l *SecretScanningLocation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type    string          `json:"type"`
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	l.Type = raw.Type
+	l.RawDetails = raw.Details
+	l.Details = nil
+
+	if len(raw.Details) == 0 || string(raw.Details) == "null" {
+		return nil
+	}
+
+	switch raw.Type {
+	case "commit", "wiki_commit":
+		l.Details = new(SecretScanningCommitLocation)
+	case "issue_title", "issue_body", "issue_comment":
+		l.Details = new(SecretScanningIssueLocation)
+	case "discussion_title", "discussion_body", "discussion_comment":
+		l.Details = new(SecretScanningDiscussionLocation)
+	case "pull_request_title", "pull_request_body", "pull_request_comment",
+		"pull_request_review", "pull_request_review_comment":
+		l.Details = new(SecretScanningPullRequestLocation)
+	default:
+		return nil
+	}
+
+	return json.Unmarshal(raw.Details, l.Details)
+}
+
+// SecretScanningScanPayload represents the webhook payload sent for secret_scanning_scan events.
+type SecretScanningScanPayload struct {
+	WebhookPayload
+	Type               string     `json:"type"`
+	Source             string     `json:"source"`
+	StartedAt          Timestamp  `json:"started_at"`
+	CompletedAt        *Timestamp `json:"completed_at"`
+	SecretTypes        []string   `json:"secret_types,omitempty"`
+	CustomPatternName  *string    `json:"custom_pattern_name,omitempty"`
+	CustomPatternScope *string    `json:"custom_pattern_scope,omitempty"`
+}
 
 // SecurityAdvisoryPayload represents the webhook payload sent for security_advisory events.
 type SecurityAdvisoryPayload struct {
 	WebhookPayload
-	SecurityAdvisory struct {
-		GHSAID      string `json:"ghsa_id"`
-		CVEID       string `json:"cve_id"`
-		Summary     string `json:"summary"`
-		Description string `json:"description"`
-		Severity    string `json:"severity"`
-		Identifiers []struct {
-			Type  string `json:"type"`
-			Value string `json:"value"`
-		} `json:"identifiers"`
-		References      json.RawMessage `json:"references"`
-		PublishedAt     Timestamp       `json:"published_at"`
-		UpdatedAt       Timestamp       `json:"updated_at"`
-		Vulnerabilities []struct {
-			Package struct {
-				Ecosystem string `json:"ecosystem"`
-				Name      string `json:"name"`
-			} `json:"package"`
-			Severity               string `json:"severity"`
-			VulnerableVersionRange string `json:"vulnerable_version_range"`
-			FirstPatchedVersion    struct {
-				Identifier string `json:"identifier"`
-			} `json:"first_patched_version"`
-		} `json:"vulnerabilities"`
-		CVSS struct {
-			VectorString string  `json:"vector_string"`
-			Score        float64 `json:"score"`
-		} `json:"cvss"`
-		CWES []struct {
-			CWEID string `json:"cwe_id"`
-			Name  string `json:"name"`
-		} `json:"cwes"`
-	} `json:"security_advisory"`
+	SecurityAdvisory SecurityAdvisory `json:"security_advisory"`
+}
+
+// SecurityAdvisory represents a GitHub security advisory. It is shared by the
+// security_advisory and dependabot_alert payloads.
+type SecurityAdvisory struct {
+	GHSAID          string                       `json:"ghsa_id"`
+	CVEID           *string                      `json:"cve_id"`
+	Summary         string                       `json:"summary"`
+	Description     string                       `json:"description"`
+	Severity        string                       `json:"severity"`
+	Identifiers     []SecurityAdvisoryIdentifier `json:"identifiers"`
+	References      json.RawMessage              `json:"references"`
+	PublishedAt     Timestamp                    `json:"published_at"`
+	UpdatedAt       Timestamp                    `json:"updated_at"`
+	WithdrawnAt     *Timestamp                   `json:"withdrawn_at"`
+	Vulnerabilities []SecurityVulnerability      `json:"vulnerabilities"`
+	CVSS            SecurityAdvisoryCVSS         `json:"cvss"`
+	CWES            []SecurityAdvisoryCWE        `json:"cwes"`
+}
+
+// SecurityAdvisoryIdentifier represents an identifier of a security advisory, such as a GHSA or CVE ID.
+type SecurityAdvisoryIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// SecurityAdvisoryCVSS represents the CVSS score of a security advisory.
+type SecurityAdvisoryCVSS struct {
+	VectorString string  `json:"vector_string"`
+	Score        float64 `json:"score"`
+}
+
+// SecurityAdvisoryCWE represents a CWE weakness associated with a security advisory.
+type SecurityAdvisoryCWE struct {
+	CWEID string `json:"cwe_id"`
+	Name  string `json:"name"`
+}
+
+// SecurityAdvisoryPackage represents a package affected by a security advisory.
+type SecurityAdvisoryPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// SecurityVulnerability represents the vulnerable versions of a package
+// affected by a security advisory.
+type SecurityVulnerability struct {
+	Package                SecurityAdvisoryPackage `json:"package"`
+	Severity               string                  `json:"severity"`
+	VulnerableVersionRange string                  `json:"vulnerable_version_range"`
+	// FirstPatchedVersion is empty when no patched version has been released.
+	FirstPatchedVersion struct {
+		Identifier string `json:"identifier"`
+	} `json:"first_patched_version"`
+}
+
+// SecurityAndAnalysisPayload represents the webhook payload sent for
+// security_and_analysis events, when code security features are enabled or
+// disabled for a repository. Repository.SecurityAndAnalysis holds the new
+// settings.
+type SecurityAndAnalysisPayload struct {
+	WebhookPayload
+	Changes struct {
+		From struct {
+			SecurityAndAnalysis *SecurityAndAnalysis `json:"security_and_analysis"`
+		} `json:"from"`
+	} `json:"changes"`
+}
+
+// SecurityAndAnalysis represents the code security settings of a repository.
+// A nil feature is not available for the repository.
+type SecurityAndAnalysis struct {
+	AdvancedSecurity                  *SecurityAndAnalysisStatus `json:"advanced_security,omitempty"`
+	CodeSecurity                      *SecurityAndAnalysisStatus `json:"code_security,omitempty"`
+	DependabotSecurityUpdates         *SecurityAndAnalysisStatus `json:"dependabot_security_updates,omitempty"`
+	SecretScanning                    *SecurityAndAnalysisStatus `json:"secret_scanning,omitempty"`
+	SecretScanningPushProtection      *SecurityAndAnalysisStatus `json:"secret_scanning_push_protection,omitempty"`
+	SecretScanningNonProviderPatterns *SecurityAndAnalysisStatus `json:"secret_scanning_non_provider_patterns,omitempty"`
+	SecretScanningValidityChecks      *SecurityAndAnalysisStatus `json:"secret_scanning_validity_checks,omitempty"`
+}
+
+// SecurityAndAnalysisStatus represents whether a code security feature is
+// "enabled" or "disabled".
+type SecurityAndAnalysisStatus struct {
+	Status string `json:"status"`
 }
 
 // SponsorshipPayload represents the webhook payload sent for sponsorship events.
@@ -91,6 +310,20 @@ type StatusPayload struct {
 	} `json:"branches"`
 }
 
+// SubIssuesPayload represents the webhook payload sent for sub_issues events.
+// The action is sub_issue_added or sub_issue_removed when delivered for the
+// parent's repository, and parent_issue_added or parent_issue_removed when
+// delivered for the sub-issue's repository.
+type SubIssuesPayload struct {
+	WebhookPayload
+	ParentIssueID   int64       `json:"parent_issue_id"`
+	ParentIssue     *Issue      `json:"parent_issue"`
+	ParentIssueRepo *Repository `json:"parent_issue_repo,omitempty"`
+	SubIssueID      int64       `json:"sub_issue_id"`
+	SubIssue        *Issue      `json:"sub_issue"`
+	SubIssueRepo    *Repository `json:"sub_issue_repo,omitempty"`
+}
+
 // TeamPayload represents the webhook payload sent for team events.
 type TeamPayload struct {
 	WebhookPayload
@@ -130,73 +363,132 @@ type WorkflowDispatchPayload struct {
 	Workflow string            `json:"workflow"`
 }
 
-// WorkflowJobPayload represents the webhook payload sent for workflow_job events.
+// WorkflowJobPayload represents the webhook payload sent for workflow_job
+// events. The action is one of queued, waiting, in_progress or completed.
 type WorkflowJobPayload struct {
 	WebhookPayload
-	WorkflowJob struct {
-		ID          int64     `json:"id"`
-		RunID       int64     `json:"run_id"`
-		RunURL      string    `json:"run_url"`
-		NodeID      string    `json:"node_id"`
-		HeadSHA     string    `json:"head_sha"`
-		URL         string    `json:"url"`
-		Status      string    `json:"status"`
-		Conclusion  string    `json:"conclusion"`
-		StartedAt   Timestamp `json:"started_at"`
-		CompletedAt Timestamp `json:"completed_at"`
-		Name        string    `json:"name"`
-		Steps       []struct {
-			Name        string    `json:"name"`
-			Status      string    `json:"status"`
-			Conclusion  string    `json:"conclusion"`
-			Number      int       `json:"number"`
-			StartedAt   Timestamp `json:"started_at"`
-			CompletedAt Timestamp `json:"completed_at"`
-		} `json:"steps"`
-		CheckRunURL     string   `json:"check_run_url"`
-		Labels          []string `json:"labels"`
-		RunnerID        int64    `json:"runner_id"`
-		RunnerName      string   `json:"runner_name"`
-		RunnerGroupID   int64    `json:"runner_group_id"`
-		RunnerGroupName string   `json:"runner_group_name"`
-	} `json:"workflow_job"`
-}
-
-// WorkflowRunPayload represents the webhook payload sent for workflow_run events.
+	WorkflowJob WorkflowJob `json:"workflow_job"`
+	// Deployment is set when the job deploys to an environment.
+	Deployment *Deployment `json:"deployment,omitempty"`
+}
+
+// WorkflowJob represents a job in a GitHub Actions workflow run. Fields that
+// are only known once the job has been picked up by a runner or has finished
+// are nil until then.
+type WorkflowJob struct {
+	ID              int64          `json:"id"`
+	RunID           int64          `json:"run_id"`
+	RunURL          string         `json:"run_url"`
+	RunAttempt      int            `json:"run_attempt"`
+	NodeID          string         `json:"node_id"`
+	HeadSHA         string         `json:"head_sha"`
+	HeadBranch      *string        `json:"head_branch"`
+	URL             string         `json:"url"`
+	HTMLURL         *string        `json:"html_url"`
+	Status          string         `json:"status"`
+	Conclusion      *string        `json:"conclusion"`
+	CreatedAt       Timestamp      `json:"created_at"`
+	StartedAt       Timestamp      `json:"started_at"`
+	CompletedAt     *Timestamp     `json:"completed_at"`
+	Name            string         `json:"name"`
+	WorkflowName    *string        `json:"workflow_name"`
+	Steps           []WorkflowStep `json:"steps"`
+	CheckRunURL     string         `json:"check_run_url"`
+	Labels          []string       `json:"labels"`
+	RunnerID        *int64         `json:"runner_id"`
+	RunnerName      *string        `json:"runner_name"`
+	RunnerGroupID   *int64         `json:"runner_group_id"`
+	RunnerGroupName *string        `json:"runner_group_name"`
+}
+
+// QueueDuration returns how long the job waited for a runner, from its
+// creation until it started. It is zero while the job is still queued.
+func (j *WorkflowJob) QueueDuration() time.Duration {
+	if j.Status == "queued" || j.Status == "waiting" || j.CreatedAt.IsZero() || j.StartedAt.Before(j.CreatedAt.Time) {
+		return 0
+	}
+	return j.StartedAt.Sub(j.CreatedAt.Time)
+}
+
+// WorkflowStep represents a step of a workflow job.
+type WorkflowStep struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  *string    `json:"conclusion"`
+	Number      int        `json:"number"`
+	StartedAt   *Timestamp `json:"started_at"`
+	CompletedAt *Timestamp `json:"completed_at"`
+}
+
+// WorkflowRunPayload represents the webhook payload sent for workflow_run
+// events. The action is one of requested, in_progress or completed.
 type WorkflowRunPayload struct {
 	WebhookPayload
-	WorkflowRun struct {
-		ID                 int64         `json:"id"`
-		NodeID             string        `json:"node_id"`
-		Name               string        `json:"name"`
-		HeadBranch         string        `json:"head_branch"`
-		HeadSHA            string        `json:"head_sha"`
-		Path               string        `json:"path"`
-		RunNumber          int           `json:"run_number"`
-		Event              string        `json:"event"`
-		Status             string        `json:"status"`
-		Conclusion         string        `json:"conclusion"`
-		WorkflowID         int64         `json:"workflow_id"`
-		CheckSuiteID       int64         `json:"check_suite_id"`
-		CheckSuiteNodeID   string        `json:"check_suite_node_id"`
-		URL                string        `json:"url"`
-		HTMLURL            string        `json:"html_url"`
-		PullRequests       []PullRequest `json:"pull_requests"`
-		CreatedAt          Timestamp     `json:"created_at"`
-		UpdatedAt          Timestamp     `json:"updated_at"`
-		RunStartedAt       Timestamp     `json:"run_started_at"`
-		JobsURL            string        `json:"jobs_url"`
-		LogsURL            string        `json:"logs_url"`
-		CheckSuiteURL      string        `json:"check_suite_url"`
-		ArtifactsURL       string        `json:"artifacts_url"`
-		CancelURL          string        `json:"cancel_url"`
-		RerunURL           string        `json:"rerun_url"`
-		PreviousAttemptURL string        `json:"previous_attempt_url"`
-		WorkflowURL        string        `json:"workflow_url"`
-		HeadCommit         Commit        `json:"head_commit"`
-		Repository         Repository    `json:"repository"`
-		HeadRepository     Repository    `json:"head_repository"`
-	} `json:"workflow_run"`
+	WorkflowRun WorkflowRun `json:"workflow_run"`
+	Workflow    *Workflow   `json:"workflow"`
+}
+
+// WorkflowRun represents a single run of a GitHub Actions workflow. Fields
+// GitHub sends as null are pointers: HeadBranch is nil for runs not triggered
+// from a branch, and Conclusion is nil until the run completes.
+type WorkflowRun struct {
+	ID                  int64                `json:"id"`
+	NodeID              string               `json:"node_id"`
+	Name                string               `json:"name"`
+	DisplayTitle        string               `json:"display_title"`
+	HeadBranch          *string              `json:"head_branch"`
+	HeadSHA             string               `json:"head_sha"`
+	Path                string               `json:"path"`
+	RunNumber           int                  `json:"run_number"`
+	RunAttempt          int                  `json:"run_attempt"`
+	Event               string               `json:"event"`
+	Status              string               `json:"status"`
+	Conclusion          *string              `json:"conclusion"`
+	WorkflowID          int64                `json:"workflow_id"`
+	CheckSuiteID        int64                `json:"check_suite_id"`
+	CheckSuiteNodeID    string               `json:"check_suite_node_id"`
+	URL                 string               `json:"url"`
+	HTMLURL             string               `json:"html_url"`
+	PullRequests        []PullRequest        `json:"pull_requests"`
+	ReferencedWorkflows []ReferencedWorkflow `json:"referenced_workflows"`
+	Actor               *User                `json:"actor"`
+	TriggeringActor     *User                `json:"triggering_actor"`
+	CreatedAt           Timestamp            `json:"created_at"`
+	UpdatedAt           Timestamp            `json:"updated_at"`
+	RunStartedAt        Timestamp            `json:"run_started_at"`
+	JobsURL             string               `json:"jobs_url"`
+	LogsURL             string               `json:"logs_url"`
+	CheckSuiteURL       string               `json:"check_suite_url"`
+	ArtifactsURL        string               `json:"artifacts_url"`
+	CancelURL           string               `json:"cancel_url"`
+	RerunURL            string               `json:"rerun_url"`
+	PreviousAttemptURL  *string              `json:"previous_attempt_url"`
+	WorkflowURL         string               `json:"workflow_url"`
+	HeadCommit          *Commit              `json:"head_commit"`
+	Repository          Repository           `json:"repository"`
+	HeadRepository      Repository           `json:"head_repository"`
+}
+
+// ReferencedWorkflow represents a reusable workflow called by a workflow run.
+type ReferencedWorkflow struct {
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+	Ref  string `json:"ref,omitempty"`
+}
+
+// Workflow represents a GitHub Actions workflow file. State is one of active,
+// deleted, disabled_fork, disabled_inactivity or disabled_manually.
+type Workflow struct {
+	ID        int64     `json:"id"`
+	NodeID    string    `json:"node_id"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	State     string    `json:"state"`
+	CreatedAt Timestamp `json:"created_at"`
+	UpdatedAt Timestamp `json:"updated_at"`
+	URL       string    `json:"url"`
+	HTMLURL   string    `json:"html_url"`
+	BadgeURL  string    `json:"badge_url"`
 }
 
 // RegistryPackagePayload represents the webhook payload sent for registry_package events.
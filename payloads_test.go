@@ -0,0 +1,453 @@
+package github
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// decodeIssues decodes an issues fixture the same way ParseWebhook does.
+func decodeIssues(t *testing.T, body string) *IssuesPayload {
+	t.Helper()
+	payload, ok := NewPayload(IssuesEvent).(*IssuesPayload)
+	if !ok {
+		t.Fatalf("NewPayload(IssuesEvent) returned %T", NewPayload(IssuesEvent))
+	}
+	if err := json.Unmarshal([]byte(body), payload); err != nil {
+		t.Fatalf("decoding issues payload: %v", err)
+	}
+	return payload
+}
+
+func TestIssuesPayloadAssignment(t *testing.T) {
+	for _, action := range []string{"assigned", "unassigned"} {
+		p := decodeIssues(t, `{
+			"action": "`+action+`",
+			"issue": {"number": 7, "state": "open", "assignees": []},
+			"assignee": {"login": "octocat", "id": 1}
+		}`)
+		if p.Action != action {
+			t.Errorf("%s: Action = %q", action, p.Action)
+		}
+		if p.Assignee == nil || p.Assignee.Login != "octocat" {
+			t.Errorf("%s: Assignee = %+v, want octocat", action, p.Assignee)
+		}
+		if p.Label != nil || p.Milestone != nil || p.Type != nil {
+			t.Errorf("%s: unexpected fields set: %+v %+v %+v", action, p.Label, p.Milestone, p.Type)
+		}
+	}
+}
+
+func TestIssuesPayloadLabels(t *testing.T) {
+	for _, action := range []string{"labeled", "unlabeled"} {
+		p := decodeIssues(t, `{
+			"action": "`+action+`",
+			"issue": {"number": 7, "state": "open", "labels": [{"id": 3, "name": "bug"}]},
+			"label": {"id": 3, "name": "bug", "color": "d73a4a"}
+		}`)
+		if p.Label == nil || p.Label.Name != "bug" || p.Label.Color != "d73a4a" {
+			t.Errorf("%s: Label = %+v, want bug", action, p.Label)
+		}
+		if p.Assignee != nil {
+			t.Errorf("%s: Assignee = %+v, want nil", action, p.Assignee)
+		}
+	}
+}
+
+func TestIssuesPayloadMilestones(t *testing.T) {
+	for _, action := range []string{"milestoned", "demilestoned"} {
+		p := decodeIssues(t, `{
+			"action": "`+action+`",
+			"issue": {"number": 7, "state": "open"},
+			"milestone": {"id": 9, "number": 2, "title": "v1.0", "state": "open"}
+		}`)
+		if p.Milestone == nil || p.Milestone.Title != "v1.0" || p.Milestone.Number != 2 {
+			t.Errorf("%s: Milestone = %+v, want v1.0", action, p.Milestone)
+		}
+	}
+}
+
+func TestIssuesPayloadEdited(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "edited",
+		"issue": {"number": 7, "state": "open", "title": "New title", "body": "New body"},
+		"changes": {
+			"title": {"from": "Old title"},
+			"body": {"from": "Old body"}
+		}
+	}`)
+	if p.Changes.Title == nil || p.Changes.Title.From != "Old title" {
+		t.Errorf("Changes.Title = %+v, want Old title", p.Changes.Title)
+	}
+	if p.Changes.Body == nil || p.Changes.Body.From != "Old body" {
+		t.Errorf("Changes.Body = %+v, want Old body", p.Changes.Body)
+	}
+	if p.Changes.NewIssue != nil || p.Changes.OldIssue != nil {
+		t.Errorf("transfer fields set on an edited payload")
+	}
+}
+
+func TestIssuesPayloadTransferred(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "transferred",
+		"issue": {"number": 7, "state": "closed"},
+		"changes": {
+			"new_issue": {"number": 12, "state": "open", "title": "Moved"},
+			"new_repository": {"id": 200, "full_name": "octo/new-home"}
+		},
+		"repository": {"id": 100, "full_name": "octo/old-home"}
+	}`)
+	if p.Changes.NewIssue == nil || p.Changes.NewIssue.Number != 12 {
+		t.Errorf("Changes.NewIssue = %+v, want #12", p.Changes.NewIssue)
+	}
+	if p.Changes.NewRepository == nil || p.Changes.NewRepository.FullName != "octo/new-home" {
+		t.Errorf("Changes.NewRepository = %+v, want octo/new-home", p.Changes.NewRepository)
+	}
+	if p.Repository.FullName != "octo/old-home" {
+		t.Errorf("Repository.FullName = %q, want octo/old-home", p.Repository.FullName)
+	}
+}
+
+func TestIssuesPayloadOpenedAfterTransfer(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "opened",
+		"issue": {"number": 12, "state": "open"},
+		"changes": {
+			"old_issue": {"number": 7, "state": "closed"},
+			"old_repository": {"id": 100, "full_name": "octo/old-home"}
+		}
+	}`)
+	if p.Changes.OldIssue == nil || p.Changes.OldIssue.Number != 7 {
+		t.Errorf("Changes.OldIssue = %+v, want #7", p.Changes.OldIssue)
+	}
+	if p.Changes.OldRepository == nil || p.Changes.OldRepository.FullName != "octo/old-home" {
+		t.Errorf("Changes.OldRepository = %+v, want octo/old-home", p.Changes.OldRepository)
+	}
+}
+
+func TestIssuesPayloadTyped(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "typed",
+		"issue": {"number": 7, "state": "open", "type": {"id": 4, "name": "Bug", "description": null}},
+		"type": {"id": 4, "node_id": "IT_4", "name": "Bug", "description": null, "color": "red", "is_enabled": true}
+	}`)
+	if p.Type == nil || p.Type.Name != "Bug" || p.Type.Description != nil || !p.Type.IsEnabled {
+		t.Errorf("Type = %+v, want enabled Bug", p.Type)
+	}
+	if p.Issue.Type == nil || p.Issue.Type.ID != 4 {
+		t.Errorf("Issue.Type = %+v, want id 4", p.Issue.Type)
+	}
+}
+
+func TestIssuesPayloadClosedStateReason(t *testing.T) {
+	for _, reason := range []string{"completed", "not_planned", "duplicate"} {
+		p := decodeIssues(t, `{
+			"action": "closed",
+			"issue": {"number": 7, "state": "closed", "state_reason": "`+reason+`"}
+		}`)
+		if p.Issue.State != "closed" {
+			t.Errorf("%s: Issue.State = %q, want closed", reason, p.Issue.State)
+		}
+		if p.Issue.StateReason == nil || *p.Issue.StateReason != reason {
+			t.Errorf("Issue.StateReason = %v, want %s", p.Issue.StateReason, reason)
+		}
+	}
+
+	p := decodeIssues(t, `{"action": "reopened", "issue": {"number": 7, "state": "open", "state_reason": null}}`)
+	if p.Issue.StateReason != nil {
+		t.Errorf("Issue.StateReason = %q, want nil", *p.Issue.StateReason)
+	}
+}
+
+func TestIssuesPayloadUntyped(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "untyped",
+		"issue": {"number": 7, "state": "open", "type": null},
+		"type": {"id": 4, "node_id": "IT_4", "name": "Bug", "description": "Something is broken"}
+	}`)
+	if p.Type == nil || p.Type.Name != "Bug" || p.Type.Description == nil || *p.Type.Description != "Something is broken" {
+		t.Errorf("Type = %+v, want the removed Bug type", p.Type)
+	}
+	if p.Issue.Type != nil {
+		t.Errorf("Issue.Type = %+v, want nil", p.Issue.Type)
+	}
+}
+
+func TestIssuesPayloadLocking(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "locked",
+		"issue": {"number": 7, "state": "open", "locked": true, "active_lock_reason": "too heated"}
+	}`)
+	if !p.Issue.Locked || p.Issue.ActiveLockReason != "too heated" {
+		t.Errorf("locked: Locked = %v, ActiveLockReason = %q; want true and too heated", p.Issue.Locked, p.Issue.ActiveLockReason)
+	}
+
+	p = decodeIssues(t, `{
+		"action": "unlocked",
+		"issue": {"number": 7, "state": "open", "locked": false, "active_lock_reason": null}
+	}`)
+	if p.Issue.Locked || p.Issue.ActiveLockReason != "" {
+		t.Errorf("unlocked: Locked = %v, ActiveLockReason = %q; want false and empty", p.Issue.Locked, p.Issue.ActiveLockReason)
+	}
+}
+
+func TestIssuesPayloadPinning(t *testing.T) {
+	for _, action := range []string{"pinned", "unpinned"} {
+		p := decodeIssues(t, `{
+			"action": "`+action+`",
+			"issue": {"number": 7, "state": "open", "title": "Roadmap"},
+			"repository": {"id": 100, "full_name": "octo/hello"}
+		}`)
+		if p.Action != action || p.Issue.Number != 7 || p.Issue.Title != "Roadmap" {
+			t.Errorf("%s: Action = %q, Issue = #%d %q", action, p.Action, p.Issue.Number, p.Issue.Title)
+		}
+		if p.Repository.FullName != "octo/hello" {
+			t.Errorf("%s: Repository.FullName = %q, want octo/hello", action, p.Repository.FullName)
+		}
+	}
+}
+
+func TestIssuesPayloadDeleted(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "deleted",
+		"issue": {"id": 1001, "number": 7, "state": "open", "title": "Spam"},
+		"sender": {"login": "octocat", "id": 1}
+	}`)
+	if p.Action != "deleted" || p.Issue.ID != 1001 || p.Issue.Number != 7 {
+		t.Errorf("Action = %q, Issue = %d #%d; want the deleted issue", p.Action, p.Issue.ID, p.Issue.Number)
+	}
+	if p.Sender.Login != "octocat" {
+		t.Errorf("Sender.Login = %q, want octocat", p.Sender.Login)
+	}
+}
+
+func TestIssuesPayloadReopenedStateReason(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "reopened",
+		"issue": {"number": 7, "state": "open", "state_reason": "reopened"}
+	}`)
+	if p.Issue.State != "open" {
+		t.Errorf("Issue.State = %q, want open", p.Issue.State)
+	}
+	if p.Issue.StateReason == nil || *p.Issue.StateReason != "reopened" {
+		t.Errorf("Issue.StateReason = %v, want reopened", p.Issue.StateReason)
+	}
+}
+
+func TestIssuesPayloadReactions(t *testing.T) {
+	p := decodeIssues(t, `{
+		"action": "edited",
+		"issue": {
+			"number": 7,
+			"state": "open",
+			"reactions": {
+				"url": "https://api.github.com/repos/octo/hello/issues/7/reactions",
+				"total_count": 21,
+				"+1": 5,
+				"-1": 2,
+				"laugh": 1,
+				"confused": 3,
+				"heart": 4,
+				"hooray": 1,
+				"eyes": 2,
+				"rocket": 3
+			}
+		}
+	}`)
+	r := p.Issue.Reactions
+	if r == nil {
+		t.Fatal("Issue.Reactions = nil")
+	}
+	want := Reactions{
+		URL:        "https://api.github.com/repos/octo/hello/issues/7/reactions",
+		TotalCount: 21,
+		PlusOne:    5,
+		MinusOne:   2,
+		Laugh:      1,
+		Confused:   3,
+		Heart:      4,
+		Hooray:     1,
+		Eyes:       2,
+		Rocket:     3,
+	}
+	if *r != want {
+		t.Errorf("Issue.Reactions = %+v, want %+v", *r, want)
+	}
+}
+
+func TestProjectsV2FieldValueChange(t *testing.T) {
+	var p ProjectsV2ItemPayload
+	err := json.Unmarshal([]byte(`{
+		"action": "edited",
+		"changes": {"field_value": {
+			"field_type": "single_select",
+			"from": {"id": "a1", "name": "Todo"},
+			"to": {"id": "b2", "name": "Done"}
+		}}
+	}`), &p)
+	if err != nil {
+		t.Fatalf("decoding projects_v2_item payload: %v", err)
+	}
+	to, ok := p.Changes.FieldValue.To.(*ProjectsV2SingleSelectOption)
+	if !ok || to.Name != "Done" {
+		t.Fatalf("To = %#v, want the Done option", p.Changes.FieldValue.To)
+	}
+}
+
+func TestProjectsV2FieldValueChangeUnexpectedShape(t *testing.T) {
+	var p ProjectsV2ItemPayload
+	err := json.Unmarshal([]byte(`{
+		"action": "edited",
+		"changes": {"field_value": {
+			"field_type": "number",
+			"from": "not a number",
+			"to": 3
+		}}
+	}`), &p)
+	if err != nil {
+		t.Fatalf("an unexpected field value shape failed the payload: %v", err)
+	}
+	change := p.Changes.FieldValue
+	if change.From != nil || string(change.RawFrom) != `"not a number"` {
+		t.Fatalf("From = %#v, RawFrom = %s; want nil and the raw value", change.From, change.RawFrom)
+	}
+	if change.To != ProjectsV2NumberValue(3) {
+		t.Fatalf("To = %#v, want 3", change.To)
+	}
+}
+
+func TestDeploymentReviewerRoundTrip(t *testing.T) {
+	var p DeploymentReviewPayload
+	err := json.Unmarshal([]byte(`{
+		"action": "requested",
+		"since": "2024-05-01T10:00:00Z",
+		"reviewers": [
+			{"type": "User", "reviewer": {"login": "octocat", "id": 1}},
+			{"type": "Team", "reviewer": {"name": "ops", "id": 2, "slug": "ops"}}
+		]
+	}`), &p)
+	if err != nil {
+		t.Fatalf("decoding deployment_review payload: %v", err)
+	}
+	if p.Since.Year() != 2024 {
+		t.Fatalf("Since = %v, want 2024-05-01", p.Since)
+	}
+	if len(p.Reviewers) != 2 || p.Reviewers[0].User == nil || p.Reviewers[1].Team == nil {
+		t.Fatalf("Reviewers = %+v, want a user and a team", p.Reviewers)
+	}
+
+	data, err := json.Marshal(p.Reviewers)
+	if err != nil {
+		t.Fatalf("encoding reviewers: %v", err)
+	}
+	var again []DeploymentReviewer
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatalf("decoding encoded reviewers: %v", err)
+	}
+	if again[0].User == nil || again[0].User.Login != "octocat" || again[1].Team == nil || again[1].Team.Slug != "ops" {
+		t.Fatalf("round trip lost reviewers: %s", data)
+	}
+}
+
+func TestSecretScanningLocationDetails(t *testing.T) {
+	for body, wantNil := range map[string]bool{
+		`{"type": "commit", "details": {"path": "main.go", "blob_sha": "abc"}}`: false,
+		`{"type": "commit", "details": null}`:                                   true,
+		`{"type": "commit"}`:                                                    true,
+		`{"type": "something_new", "details": {"url": "x"}}`:                    true,
+	} {
+		var l SecretScanningLocation
+		if err := json.Unmarshal([]byte(body), &l); err != nil {
+			t.Fatalf("decoding %s: %v", body, err)
+		}
+		if (l.Details == nil) != wantNil {
+			t.Errorf("%s: Details = %#v, want nil: %v", body, l.Details, wantNil)
+		}
+	}
+}
+
+func TestSecurityAdvisoryCVEID(t *testing.T) {
+	var withCVE, withoutCVE SecurityAdvisory
+	if err := json.Unmarshal([]byte(`{"ghsa_id": "GHSA-1", "cve_id": "CVE-2024-1"}`), &withCVE); err != nil {
+		t.Fatalf("decoding advisory: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"ghsa_id": "GHSA-2", "cve_id": null}`), &withoutCVE); err != nil {
+		t.Fatalf("decoding advisory: %v", err)
+	}
+	if withCVE.CVEID == nil || *withCVE.CVEID != "CVE-2024-1" {
+		t.Errorf("CVEID = %v, want CVE-2024-1", withCVE.CVEID)
+	}
+	if withoutCVE.CVEID != nil {
+		t.Errorf("CVEID = %q, want nil", *withoutCVE.CVEID)
+	}
+}
+
+func TestWorkflowRunNullableFields(t *testing.T) {
+	var p WorkflowRunPayload
+	err := json.Unmarshal([]byte(`{
+		"action": "requested",
+		"workflow_run": {"id": 1, "head_branch": null, "status": "queued", "conclusion": null}
+	}`), &p)
+	if err != nil {
+		t.Fatalf("decoding workflow_run payload: %v", err)
+	}
+	if p.WorkflowRun.HeadBranch != nil || p.WorkflowRun.Conclusion != nil {
+		t.Fatalf("HeadBranch = %v, Conclusion = %v; want both nil", p.WorkflowRun.HeadBranch, p.WorkflowRun.Conclusion)
+	}
+}
+
+// deployTarget is an example of a payload attached to a deployment.
+type deployTarget struct {
+	Region   string `json:"region"`
+	Replicas int    `json:"replicas"`
+}
+
+func TestDeploymentDecodePayloadObject(t *testing.T) {
+	var p DeploymentPayload
+	if err := json.Unmarshal([]byte(`{"deployment": {"id": 1, "payload": {"region": "eu", "replicas": 3}}}`), &p); err != nil {
+		t.Fatalf("decoding deployment payload: %v", err)
+	}
+	var target deployTarget
+	if err := p.Deployment.DecodePayload(&target); err != nil {
+		t.Fatalf("DecodePayload: %v", err)
+	}
+	if target != (deployTarget{"eu", 3}) {
+		t.Fatalf("DecodePayload = %+v, want eu with 3 replicas", target)
+	}
+}
+
+func TestDeploymentDecodePayloadEncodedString(t *testing.T) {
+	var p DeploymentPayload
+	if err := json.Unmarshal([]byte(`{"deployment": {"id": 1, "payload": "{\"region\": \"us\", \"replicas\": 2}"}}`), &p); err != nil {
+		t.Fatalf("decoding deployment payload: %v", err)
+	}
+	var target deployTarget
+	if err := p.Deployment.DecodePayload(&target); err != nil {
+		t.Fatalf("DecodePayload: %v", err)
+	}
+	if target != (deployTarget{"us", 2}) {
+		t.Fatalf("DecodePayload = %+v, want us with 2 replicas", target)
+	}
+}
+
+func TestDeploymentDecodePayloadEmpty(t *testing.T) {
+	for _, payload := range []string{``, `null`, `""`, `{}`, `"{}"`} {
+		d := Deployment{Payload: json.RawMessage(payload)}
+		target := deployTarget{"untouched", 1}
+		if err := d.DecodePayload(&target); err != nil {
+			t.Errorf("DecodePayload(%s): %v", payload, err)
+		}
+		if target != (deployTarget{"untouched", 1}) {
+			t.Errorf("DecodePayload(%s) modified the target: %+v", payload, target)
+		}
+	}
+}
+
+func TestDeploymentDecodePayloadInvalid(t *testing.T) {
+	for _, payload := range []string{`{"region":`, `"{not json}"`, `"unterminated`, `[1, 2]`} {
+		d := Deployment{Payload: json.RawMessage(payload)}
+		var target deployTarget
+		if err := d.DecodePayload(&target); err == nil {
+			t.Errorf("DecodePayload(%s) succeeded, want an error", payload)
+		}
+	}
+}
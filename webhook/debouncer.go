@@ -0,0 +1,328 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// ErrDebouncerClosed is returned by Add once the debouncer has been closed.
+var ErrDebouncerClosed = errors.New("debouncer is closed")
+
+// Batch is a group of events that arrived for the same key within a quiet window.
+type Batch struct {
+	// Key is the coalescing key shared by every event in the batch.
+	Key string
+	// Events holds every coalesced event in arrival order.
+	Events []*github.WebhookEvent
+	// FirstSeen is when the first event in the batch was added.
+	FirstSeen time.Time
+	// LastSeen is when the most recent event in the batch was added.
+	LastSeen time.Time
+}
+
+// Latest returns the most recent event in the batch.
+func (b *Batch) Latest() *github.WebhookEvent {
+	if len(b.Events) == 0 {
+		return nil
+	}
+	return b.Events[len(b.Events)-1]
+}
+
+// CommitSHAs returns the head commit SHAs seen across the batch, in arrival
+// order and without duplicates. Push events contribute every pushed commit and
+// pull request events contribute their head SHA, so a batch produced by a
+// force-push storm lists each intermediate head that was skipped.
+func (b *Batch) CommitSHAs() []string {
+	var shas []string
+	seen := make(map[string]bool)
+
+	add := func(sha string) {
+		if sha == "" || seen[sha] {
+			return
+		}
+		seen[sha] = true
+		shas = append(shas, sha)
+	}
+
+	for _, event := range b.Events {
+		switch p := event.Payload.(type) {
+		case *github.PushPayload:
+			for _, commit := range p.Commits {
+				add(commit.ID)
+			}
+			if !p.Deleted {
+				add(p.After)
+			}
+		case *github.PullRequestPayload:
+			add(p.PullRequest.Head.SHA)
+		}
+	}
+
+	return shas
+}
+
+// LatestOnly adapts an event callback for use with a debouncer so that only the
+// most recent event of each batch is processed.
+func LatestOnly(callback func(*github.WebhookEvent) error) func(*Batch) error {
+	return func(b *Batch) error {
+		return callback(b.Latest())
+	}
+}
+
+// DebouncerOption configures a Debouncer.
+type DebouncerOption func(*Debouncer)
+
+// WithQuietWindow sets how long a key must go without new events before its
+// batch is emitted. It defaults to 10 seconds.
+func WithQuietWindow(d time.Duration) DebouncerOption {
+	return func(db *Debouncer) {
+		if d > 0 {
+			db.quiet = d
+		}
+	}
+}
+
+// WithMaxWait bounds how long a batch may be held after its first event, so a
+// key that never goes quiet is still emitted periodically. It defaults to one
+// minute.
+func WithMaxWait(d time.Duration) DebouncerOption {
+	return func(db *Debouncer) {
+		if d > 0 {
+			db.maxWait = d
+		}
+	}
+}
+
+// WithDebounceKey sets the function used to group events. Events for which it
+// returns an empty key are emitted immediately as single-event batches, subject
+// to the limit set by WithMaxConcurrentEmits.
+func WithDebounceKey(fn KeyFunc) DebouncerOption {
+	return func(db *Debouncer) {
+		db.keyFunc = fn
+	}
+}
+
+// WithMaxConcurrentEmits limits how many single-event batches, emitted for
+// events without a key, may run at the same time. Once the limit is reached
+// Add blocks until one of them returns. Values below one are ignored. It
+// defaults to 16.
+func WithMaxConcurrentEmits(n int) DebouncerOption {
+	return func(db *Debouncer) {
+		if n > 0 {
+			db.maxEmits = n
+		}
+	}
+}
+
+// WithBatchErrorHandler sets a function that is called whenever the emit
+// callback returns an error or panics.
+func WithBatchErrorHandler(fn func(*Batch, error)) DebouncerOption {
+	return func(db *Debouncer) {
+		db.onError = fn
+	}
+}
+
+// DefaultDebounceKey coalesces push events per ref and pull_request synchronize
+// events per pull request, using DefaultKeyFunc. Every other event, including
+// other pull_request actions such as opened or closed, gets an empty key and
+// is emitted on its own, so LatestOnly never drops it. Those events are not
+// held back, so they may be emitted before a pending batch for the same
+// resource; at most WithMaxConcurrentEmits of them run at once.
+func DefaultDebounceKey(event *github.WebhookEvent) string {
+	switch p := event.Payload.(type) {
+	case *github.PushPayload:
+	case *github.PullRequestPayload:
+		if p.Action != "synchronize" {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	key := DefaultKeyFunc(event)
+	if key == "" {
+		return ""
+	}
+	return string(event.Type) + ":" + key
+}
+
+// pendingBatch is a batch waiting for its quiet window or max wait to elapse.
+type pendingBatch struct {
+	batch *Batch
+	timer *time.Timer
+	due   time.Time
+}
+
+// Debouncer coalesces bursts of webhook events for the same resource and emits
+// them as a single batch once the resource has been quiet for a configured
+// window, or once the batch has been held for the maximum wait.
+type Debouncer struct {
+	emit    func(*Batch) error
+	keyFunc KeyFunc
+	quiet   time.Duration
+	maxWait time.Duration
+	onError func(*Batch, error)
+
+	maxEmits int
+	emits    chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]*pendingBatch
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewDebouncer creates a debouncer that passes each completed batch to emit.
+func NewDebouncer(emit func(*Batch) error, opts ...DebouncerOption) *Debouncer {
+	d := &Debouncer{
+		emit:    emit,
+		keyFunc: DefaultDebounceKey,
+		quiet:   10 * time.Second,
+		maxWait: time.Minute,
+		pending: make(map[string]*pendingBatch),
+
+		maxEmits: 16,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.emits = make(chan struct{}, d.maxEmits)
+	return d
+}
+
+// Add records an event. Its signature matches the callback accepted by
+// Handler.HandleWebhook and NewDispatcher.
+func (d *Debouncer) Add(event *github.WebhookEvent) error {
+	key := d.keyFunc(event)
+	now := time.Now()
+
+	// Events without a key are not coalesced
+	if key == "" {
+		return d.emitNow(&Batch{Events: []*github.WebhookEvent{event}, FirstSeen: now, LastSeen: now})
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDebouncerClosed
+	}
+
+	p, ok := d.pending[key]
+	if !ok {
+		p = &pendingBatch{batch: &Batch{Key: key, FirstSeen: now}}
+		d.pending[key] = p
+	}
+	p.batch.Events = append(p.batch.Events, event)
+	p.batch.LastSeen = now
+
+	// Wait for the quiet window, but never beyond the max wait
+	p.due = now.Add(d.quiet)
+	if deadline := p.batch.FirstSeen.Add(d.maxWait); deadline.Before(p.due) {
+		p.due = deadline
+	}
+
+	if p.timer == nil {
+		p.timer = time.AfterFunc(p.due.Sub(now), func() { d.fire(key, p) })
+	} else {
+		p.timer.Reset(p.due.Sub(now))
+	}
+
+	return nil
+}
+
+// emitNow emits a single-event batch in the background once fewer than the
+// maximum number of such batches are running.
+func (d *Debouncer) emitNow(b *Batch) error {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return ErrDebouncerClosed
+	}
+	d.wg.Add(1)
+	d.mu.Unlock()
+
+	d.emits <- struct{}{}
+	go func() {
+		defer func() { <-d.emits }()
+		d.run(b)
+	}()
+	return nil
+}
+
+// fire emits a pending batch once it is due.
+func (d *Debouncer) fire(key string, p *pendingBatch) {
+	d.mu.Lock()
+	// The batch may have been flushed already, or extended by a later event
+	// after this timer fired but before it acquired the lock
+	if d.pending[key] != p || time.Now().Before(p.due) {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.pending, key)
+	d.wg.Add(1)
+	d.mu.Unlock()
+
+	d.run(p.batch)
+}
+
+// run passes a batch to the emit callback and reports any error. A panicking
+// callback is recovered and reported as an error so it cannot take down the
+// process from a timer goroutine.
+func (d *Debouncer) run(b *Batch) {
+	defer d.wg.Done()
+
+	if err := d.invoke(b); err != nil && d.onError != nil {
+		d.onError(b, err)
+	}
+}
+
+// invoke calls the emit callback, converting a panic into an error.
+func (d *Debouncer) invoke(b *Batch) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("batch callback panicked: %v", r)
+		}
+	}()
+	return d.emit(b)
+}
+
+// Pending returns the number of keys with a batch waiting to be emitted.
+func (d *Debouncer) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
+
+// Flush emits every pending batch immediately and waits for the emit callbacks
+// to return.
+func (d *Debouncer) Flush() {
+	d.mu.Lock()
+	batches := make([]*Batch, 0, len(d.pending))
+	for key, p := range d.pending {
+		p.timer.Stop()
+		delete(d.pending, key)
+		batches = append(batches, p.batch)
+	}
+	d.wg.Add(len(batches))
+	d.mu.Unlock()
+
+	for _, b := range batches {
+		go d.run(b)
+	}
+	d.wg.Wait()
+}
+
+// Close stops the debouncer from accepting new events and flushes every
+// pending batch.
+func (d *Debouncer) Close() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	d.Flush()
+}
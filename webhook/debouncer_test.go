@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// pullRequestEvent returns a pull_request event for pull request 1 of repository 1.
+func pullRequestEvent(action, sha string) *github.WebhookEvent {
+	payload := &github.PullRequestPayload{}
+	payload.Action = action
+	payload.Repository.ID = 1
+	payload.PullRequest.Number = 1
+	payload.PullRequest.Head.SHA = sha
+	return &github.WebhookEvent{Type: github.PullRequestEvent, DeliveryID: action + "-" + sha, Payload: payload}
+}
+
+func TestDefaultDebounceKey(t *testing.T) {
+	if key := DefaultDebounceKey(pullRequestEvent("synchronize", "a")); key != "pull_request:1#1" {
+		t.Errorf("synchronize key = %q, want %q", key, "pull_request:1#1")
+	}
+	for _, action := range []string{"opened", "closed", "labeled", "reopened"} {
+		if key := DefaultDebounceKey(pullRequestEvent(action, "a")); key != "" {
+			t.Errorf("%s key = %q, want empty", action, key)
+		}
+	}
+
+	push := &github.PushPayload{Ref: "refs/heads/main"}
+	push.Repository.ID = 1
+	if key := DefaultDebounceKey(&github.WebhookEvent{Type: github.PushEvent, Payload: push}); key != "push:1@refs/heads/main" {
+		t.Errorf("push key = %q, want %q", key, "push:1@refs/heads/main")
+	}
+}
+
+func TestDebouncerPassesThroughOtherActions(t *testing.T) {
+	batches := make(chan *Batch, 4)
+	d := NewDebouncer(func(b *Batch) error {
+		batches <- b
+		return nil
+	}, WithQuietWindow(time.Hour))
+	defer d.Close()
+
+	for _, event := range []*github.WebhookEvent{
+		pullRequestEvent("opened", "a"),
+		pullRequestEvent("synchronize", "b"),
+		pullRequestEvent("closed", "b"),
+	} {
+		if err := d.Add(event); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	emitted := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case b := <-batches:
+			emitted[b.Latest().DeliveryID] = true
+		case <-time.After(time.Second):
+			t.Fatalf("only %v were emitted immediately", emitted)
+		}
+	}
+	if !emitted["opened-a"] || !emitted["closed-b"] {
+		t.Fatalf("emitted %v, want opened-a and closed-b", emitted)
+	}
+	if pending := d.Pending(); pending != 1 {
+		t.Fatalf("Pending = %d, want 1", pending)
+	}
+}
+
+func TestDebouncerEmitsAfterQuietWindow(t *testing.T) {
+	batches := make(chan *Batch, 1)
+	d := NewDebouncer(func(b *Batch) error {
+		batches <- b
+		return nil
+	}, WithQuietWindow(50*time.Millisecond), WithMaxWait(time.Hour))
+	defer d.Close()
+
+	start := time.Now()
+	for _, sha := range []string{"a", "b", "c"} {
+		if err := d.Add(pullRequestEvent("synchronize", sha)); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	select {
+	case b := <-batches:
+		if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+			t.Fatalf("batch emitted after %v, before the quiet window elapsed", elapsed)
+		}
+		if len(b.Events) != 3 || b.Latest().DeliveryID != "synchronize-c" {
+			t.Fatalf("unexpected batch: %d events, latest %s", len(b.Events), b.Latest().DeliveryID)
+		}
+		shas := b.CommitSHAs()
+		if len(shas) != 3 || shas[0] != "a" || shas[2] != "c" {
+			t.Fatalf("CommitSHAs = %v", shas)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batch was not emitted after the quiet window")
+	}
+}
+
+func TestDebouncerEmitsAtMaxWait(t *testing.T) {
+	batches := make(chan *Batch, 1)
+	d := NewDebouncer(func(b *Batch) error {
+		batches <- b
+		return nil
+	}, WithQuietWindow(40*time.Millisecond), WithMaxWait(100*time.Millisecond))
+	defer d.Close()
+
+	// Keep the key busy well past the max wait so the quiet window never elapses
+	stop := time.After(300 * time.Millisecond)
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for i := 0; ; i++ {
+		select {
+		case b := <-batches:
+			elapsed := time.Since(start)
+			if elapsed < 90*time.Millisecond || elapsed > 250*time.Millisecond {
+				t.Fatalf("batch emitted after %v, want about 100ms", elapsed)
+			}
+			if len(b.Events) == 0 {
+				t.Fatal("empty batch")
+			}
+			return
+		case <-ticker.C:
+			if err := d.Add(pullRequestEvent("synchronize", string(rune('a'+i%26)))); err != nil {
+				t.Fatalf("Add: %v", err)
+			}
+		case <-stop:
+			t.Fatal("batch was not emitted at the max wait")
+		}
+	}
+}
+
+func TestDebouncerCloseFlushesPending(t *testing.T) {
+	var emitted []*Batch
+	d := NewDebouncer(func(b *Batch) error {
+		emitted = append(emitted, b)
+		return nil
+	}, WithQuietWindow(time.Hour))
+
+	if err := d.Add(pullRequestEvent("synchronize", "a")); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	d.Close()
+
+	if len(emitted) != 1 {
+		t.Fatalf("emitted %d batches on Close, want 1", len(emitted))
+	}
+	if err := d.Add(pullRequestEvent("synchronize", "b")); err != ErrDebouncerClosed {
+		t.Fatalf("Add after Close = %v, want ErrDebouncerClosed", err)
+	}
+}
+
+func TestDebouncerRecoversPanics(t *testing.T) {
+	errs := make(chan error, 2)
+	d := NewDebouncer(func(b *Batch) error {
+		panic("boom: " + b.Latest().DeliveryID)
+	}, WithQuietWindow(10*time.Millisecond), WithBatchErrorHandler(func(b *Batch, err error) {
+		errs <- err
+	}))
+	defer d.Close()
+
+	// One event is emitted straight away and one from the quiet window timer
+	for _, event := range []*github.WebhookEvent{
+		pullRequestEvent("opened", "a"),
+		pullRequestEvent("synchronize", "b"),
+	} {
+		if err := d.Add(event); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			if !strings.Contains(err.Error(), "panicked") {
+				t.Fatalf("reported error = %v, want a recovered panic", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("panic was not reported to the error handler")
+		}
+	}
+}
+
+func TestDebouncerLimitsConcurrentEmits(t *testing.T) {
+	var running, peak atomic.Int32
+	d := NewDebouncer(func(b *Batch) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return nil
+	}, WithMaxConcurrentEmits(2))
+
+	for i := 0; i < 50; i++ {
+		if err := d.Add(pullRequestEvent("opened", "a")); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	d.Close()
+
+	if got := peak.Load(); got > 2 {
+		t.Fatalf("%d emits ran at once, want at most 2", got)
+	}
+}
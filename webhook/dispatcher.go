@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// ErrDispatcherClosed is returned by Dispatch once the dispatcher has been closed.
+var ErrDispatcherClosed = errors.New("dispatcher is closed")
+
+// KeyFunc returns the partition key for a webhook event. Events that share a key
+// are processed one at a time in arrival order; events with different keys may be
+// processed in parallel. An empty key means the event has no ordering constraints.
+type KeyFunc func(*github.WebhookEvent) string
+
+// DefaultKeyFunc partitions events by repository and, where the payload carries
+// one, by issue or pull request number or by git ref. Issues and pull requests
+// share a number space within a repository, so an issue_comment on a pull request
+// is ordered with the pull_request events for the same number.
+func DefaultKeyFunc(event *github.WebhookEvent) string {
+	common, ok := event.Payload.(interface {
+		Common() *github.WebhookPayload
+	})
+	if !ok {
+		return ""
+	}
+
+	repoID := common.Common().Repository.ID
+	if repoID == 0 {
+		return ""
+	}
+	key := strconv.FormatInt(repoID, 10)
+
+	switch p := event.Payload.(type) {
+	case *github.PullRequestPayload:
+		return key + "#" + strconv.Itoa(p.PullRequest.Number)
+	case *github.PullRequestReviewPayload:
+		return key + "#" + strconv.Itoa(p.PullRequest.Number)
+	case *github.PullRequestReviewCommentPayload:
+		return key + "#" + strconv.Itoa(p.PullRequest.Number)
+	case *github.PullRequestReviewThreadPayload:
+		return key + "#" + strconv.Itoa(p.PullRequest.Number)
+	case *github.IssuesPayload:
+		return key + "#" + strconv.Itoa(p.Issue.Number)
+	case *github.IssueCommentPayload:
+		return key + "#" + strconv.Itoa(p.Issue.Number)
+	case *github.PushPayload:
+		return key + "@" + p.Ref
+	case *github.CreatePayload:
+		return key + "@" + qualifyRef(p.Ref, p.RefType)
+	case *github.DeletePayload:
+		return key + "@" + qualifyRef(p.Ref, p.RefType)
+	}
+
+	return key
+}
+
+// qualifyRef turns the short ref names used by create and delete events into the
+// fully qualified form used by push events so that all three share a key.
+func qualifyRef(ref, refType string) string {
+	if strings.HasPrefix(ref, "refs/") {
+		return ref
+	}
+	switch refType {
+	case "branch":
+		return "refs/heads/" + ref
+	case "tag":
+		return "refs/tags/" + ref
+	}
+	return ref
+}
+
+// DispatcherStats is a point-in-time snapshot of a dispatcher's queues.
+type DispatcherStats struct {
+	// Pending is the number of events queued or running across all keys.
+	Pending int
+	// ActiveKeys is the number of keys with at least one queued or running event.
+	ActiveKeys int
+	// MaxKeyBacklog is the largest number of events queued or running for a single key.
+	MaxKeyBacklog int
+	// Processed is the number of events whose callback returned nil.
+	Processed uint64
+	// Failed is the number of events whose callback returned an error or panicked.
+	Failed uint64
+}
+
+// DispatcherOption configures a Dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithKeyFunc sets the function used to partition events. It defaults to DefaultKeyFunc.
+func WithKeyFunc(fn KeyFunc) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.keyFunc = fn
+	}
+}
+
+// WithWorkers limits the number of callbacks that may run at the same time.
+// Values below one are ignored. It defaults to 16.
+func WithWorkers(n int) DispatcherOption {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.workers = n
+		}
+	}
+}
+
+// WithErrorHandler sets a function that is called whenever the callback returns
+// an error. Because events are processed asynchronously, this is the only place
+// callback errors are reported.
+func WithErrorHandler(fn func(*github.WebhookEvent, error)) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.onError = fn
+	}
+}
+
+// Dispatcher runs a callback for webhook events concurrently while preserving
+// arrival order for events that share a partition key. Callbacks run on a fixed
+// pool of workers, so a burst of events never starts more goroutines than the
+// configured worker count.
+type Dispatcher struct {
+	callback func(*github.WebhookEvent) error
+	keyFunc  KeyFunc
+	workers  int
+	onError  func(*github.WebhookEvent, error)
+
+	wg sync.WaitGroup
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queues  map[string][]*github.WebhookEvent
+	ready   []task
+	pending int
+	closed  bool
+	stopped bool
+
+	processed atomic.Uint64
+	failed    atomic.Uint64
+}
+
+// task is a unit of work for a worker: either the head of a key's queue or a
+// single unkeyed event.
+type task struct {
+	key   string
+	event *github.WebhookEvent
+}
+
+// NewDispatcher creates a dispatcher that invokes callback for every dispatched event.
+func NewDispatcher(callback func(*github.WebhookEvent) error, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		callback: callback,
+		keyFunc:  DefaultKeyFunc,
+		workers:  16,
+		queues:   make(map[string][]*github.WebhookEvent),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.cond = sync.NewCond(&d.mu)
+	for i := 0; i < d.workers; i++ {
+		go d.work()
+	}
+	return d
+}
+
+// Dispatch queues an event for processing and returns immediately. Its signature
+// matches the callback accepted by Handler.HandleWebhook, so a dispatcher can be
+// plugged in directly:
+//
+//	http.HandleFunc("/webhook", handler.HandleWebhook(dispatcher.Dispatch))
+func (d *Dispatcher) Dispatch(event *github.WebhookEvent) error {
+	key := d.keyFunc(event)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+
+	d.wg.Add(1)
+	d.pending++
+
+	// Events without a key have no ordering constraints
+	if key == "" {
+		d.schedule(task{event: event})
+		return nil
+	}
+
+	// A key with a queue is already scheduled or running
+	queue, running := d.queues[key]
+	d.queues[key] = append(queue, event)
+	if !running {
+		d.schedule(task{key: key})
+	}
+
+	return nil
+}
+
+// schedule hands a task to the next idle worker. d.mu must be held.
+func (d *Dispatcher) schedule(t task) {
+	d.ready = append(d.ready, t)
+	d.cond.Signal()
+}
+
+// work runs tasks until the dispatcher is closed and no work remains. A key is
+// rescheduled after each of its events rather than drained in one go, so a busy
+// key cannot starve the others.
+func (d *Dispatcher) work() {
+	for {
+		d.mu.Lock()
+		for len(d.ready) == 0 && !d.stopped {
+			d.cond.Wait()
+		}
+		if len(d.ready) == 0 {
+			d.mu.Unlock()
+			return
+		}
+		t := d.ready[0]
+		d.ready = d.ready[1:]
+		if t.key != "" {
+			t.event = d.queues[t.key][0]
+		}
+		d.mu.Unlock()
+
+		d.run(t.event)
+
+		d.mu.Lock()
+		d.pending--
+		if t.key != "" {
+			queue := d.queues[t.key][1:]
+			if len(queue) == 0 {
+				delete(d.queues, t.key)
+			} else {
+				d.queues[t.key] = queue
+				d.schedule(task{key: t.key})
+			}
+		}
+		d.mu.Unlock()
+	}
+}
+
+// run invokes the callback and records the outcome. A panicking callback is
+// recovered and counted as a failure so it cannot take down the worker or
+// stall the key's queue.
+func (d *Dispatcher) run(event *github.WebhookEvent) {
+	defer d.wg.Done()
+
+	if err := d.invoke(event); err != nil {
+		d.failed.Add(1)
+		if d.onError != nil {
+			d.onError(event, err)
+		}
+		return
+	}
+	d.processed.Add(1)
+}
+
+// invoke calls the callback, converting a panic into an error.
+func (d *Dispatcher) invoke(event *github.WebhookEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webhook callback panicked: %v", r)
+		}
+	}()
+	return d.callback(event)
+}
+
+// Backlog returns the number of events queued or running for a key.
+func (d *Dispatcher) Backlog(key string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.queues[key])
+}
+
+// Stats returns a snapshot of the dispatcher's queues and counters. Unkeyed
+// events count towards Pending but not towards the per-key figures.
+func (d *Dispatcher) Stats() DispatcherStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stats := DispatcherStats{
+		Pending:    d.pending,
+		ActiveKeys: len(d.queues),
+		Processed:  d.processed.Load(),
+		Failed:     d.failed.Load(),
+	}
+	for _, queue := range d.queues {
+		if len(queue) > stats.MaxKeyBacklog {
+			stats.MaxKeyBacklog = len(queue)
+		}
+	}
+	return stats
+}
+
+// Close stops the dispatcher from accepting new events, waits for all queued
+// events to be processed and then stops the workers.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	d.wg.Wait()
+
+	d.mu.Lock()
+	d.stopped = true
+	d.cond.Broadcast()
+	d.mu.Unlock()
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+func TestDispatcherPreservesPerKeyOrder(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[string][]string)
+
+	d := NewDispatcher(func(event *github.WebhookEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[string(event.Type)] = append(seen[string(event.Type)], event.DeliveryID)
+		return nil
+	}, WithWorkers(4), WithKeyFunc(func(event *github.WebhookEvent) string {
+		return string(event.Type)
+	}))
+
+	var want []string
+	for i := 0; i < 50; i++ {
+		id := string(rune('a' + i%26))
+		want = append(want, id)
+		for _, key := range []string{"a", "b", "c"} {
+			if err := d.Dispatch(&github.WebhookEvent{Type: github.WebhookEventType(key), DeliveryID: id}); err != nil {
+				t.Fatalf("Dispatch: %v", err)
+			}
+		}
+	}
+	d.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		got := seen[key]
+		if len(got) != len(want) {
+			t.Fatalf("key %s: got %d events, want %d", key, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("key %s: event %d is %s, want %s", key, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestDispatcherCloseDrainsQueuedEvents(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	d := NewDispatcher(func(event *github.WebhookEvent) error {
+		<-release
+		calls.Add(1)
+		return nil
+	}, WithWorkers(2), WithKeyFunc(func(event *github.WebhookEvent) string {
+		return event.DeliveryID
+	}))
+
+	for _, id := range []string{"1", "1", "2", "", ""} {
+		if err := d.Dispatch(&github.WebhookEvent{DeliveryID: id}); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+	if pending := d.Stats().Pending; pending != 5 {
+		t.Fatalf("Pending = %d, want 5", pending)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Close returned before queued events were processed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+
+	if got := calls.Load(); got != 5 {
+		t.Fatalf("processed %d events, want 5", got)
+	}
+	if stats := d.Stats(); stats.Pending != 0 || stats.ActiveKeys != 0 || stats.Processed != 5 {
+		t.Fatalf("unexpected stats after Close: %+v", stats)
+	}
+	if err := d.Dispatch(&github.WebhookEvent{}); !errors.Is(err, ErrDispatcherClosed) {
+		t.Fatalf("Dispatch after Close = %v, want ErrDispatcherClosed", err)
+	}
+}
+
+func TestDispatcherLimitsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+
+	d := NewDispatcher(func(event *github.WebhookEvent) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return nil
+	}, WithWorkers(3), WithKeyFunc(func(*github.WebhookEvent) string { return "" }))
+
+	for i := 0; i < 100; i++ {
+		if err := d.Dispatch(&github.WebhookEvent{}); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+	d.Close()
+
+	if got := peak.Load(); got > 3 {
+		t.Fatalf("%d callbacks ran at once, want at most 3", got)
+	}
+}
+
+func TestDispatcherRecoversPanics(t *testing.T) {
+	var reported error
+
+	d := NewDispatcher(func(event *github.WebhookEvent) error {
+		if event.DeliveryID == "boom" {
+			panic("boom")
+		}
+		return nil
+	}, WithWorkers(1), WithKeyFunc(func(*github.WebhookEvent) string { return "key" }),
+		WithErrorHandler(func(event *github.WebhookEvent, err error) {
+			reported = err
+		}))
+
+	for _, id := range []string{"boom", "ok"} {
+		if err := d.Dispatch(&github.WebhookEvent{DeliveryID: id}); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+	d.Close()
+
+	stats := d.Stats()
+	if stats.Failed != 1 || stats.Processed != 1 {
+		t.Fatalf("Failed = %d, Processed = %d, want 1 and 1", stats.Failed, stats.Processed)
+	}
+	if reported == nil {
+		t.Fatal("panic was not reported to the error handler")
+	}
+}
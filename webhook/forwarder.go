@@ -0,0 +1,415 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// SignPayload returns the X-Hub-Signature-256 value for a payload signed with secret.
+func SignPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Destination is an internal service that receives forwarded deliveries.
+type Destination struct {
+	// Name identifies the destination in delivery results.
+	Name string `json:"name"`
+	// URL is the endpoint deliveries are POSTed to.
+	URL string `json:"url"`
+	// Secret is used to re-sign each delivery for this destination. If empty,
+	// deliveries are forwarded without a signature.
+	Secret string `json:"-"`
+	// Events limits forwarding to these event types. Empty means all events.
+	Events []github.WebhookEventType `json:"events,omitempty"`
+	// Actions limits forwarding to payloads with one of these actions. Empty
+	// means all actions, including payloads without one.
+	Actions []string `json:"actions,omitempty"`
+	// Repositories limits forwarding to repositories whose full name matches one
+	// of these glob patterns, such as "my-org/*". Empty means all repositories.
+	Repositories []string `json:"repositories,omitempty"`
+}
+
+// Matches reports whether a delivery with the given event type, action and
+// repository full name should be forwarded to the destination.
+func (d *Destination) Matches(eventType github.WebhookEventType, action, repository string) bool {
+	if len(d.Events) > 0 && !containsEvent(d.Events, eventType) {
+		return false
+	}
+	if len(d.Actions) > 0 && !containsString(d.Actions, action) {
+		return false
+	}
+	if len(d.Repositories) > 0 {
+		for _, pattern := range d.Repositories {
+			// Patterns are checked by Validate, so a match error cannot occur here
+			if ok, _ := path.Match(pattern, repository); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Validate checks that the destination has a URL and that its repository
+// patterns are valid globs.
+func (d *Destination) Validate() error {
+	if d.URL == "" {
+		return fmt.Errorf("destination %q has no url", d.Name)
+	}
+	for _, pattern := range d.Repositories {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("destination %q: invalid repository pattern %q: %v", d.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+func containsEvent(events []github.WebhookEventType, eventType github.WebhookEventType) bool {
+	for _, e := range events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ForwardResult is the outcome of forwarding one delivery to one destination.
+type ForwardResult struct {
+	Destination string        `json:"destination"`
+	StatusCode  int           `json:"status_code,omitempty"`
+	Attempts    int           `json:"attempts"`
+	Duration    time.Duration `json:"duration"`
+	Err         error         `json:"-"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// OK reports whether the destination accepted the delivery.
+func (r *ForwardResult) OK() bool {
+	return r.Err == nil
+}
+
+// ForwarderOption configures a Forwarder.
+type ForwarderOption func(*Forwarder)
+
+// WithHTTPClient sets the client used to contact destinations. It defaults to
+// a client with a 10 second timeout.
+func WithHTTPClient(client *http.Client) ForwarderOption {
+	return func(f *Forwarder) {
+		f.client = client
+	}
+}
+
+// WithRetry sets the maximum number of attempts per destination and the delay
+// before the first retry. The delay doubles after each failed attempt. It
+// defaults to 3 attempts starting at 500ms.
+func WithRetry(attempts int, backoff time.Duration) ForwarderOption {
+	return func(f *Forwarder) {
+		if attempts > 0 {
+			f.attempts = attempts
+		}
+		if backoff > 0 {
+			f.backoff = backoff
+		}
+	}
+}
+
+// WithResultHandler sets a function that receives the per-destination results
+// of every delivery.
+func WithResultHandler(fn func(deliveryID string, results []ForwardResult)) ForwarderOption {
+	return func(f *Forwarder) {
+		f.onResult = fn
+	}
+}
+
+// WithMaxBodyBytes limits the size of incoming deliveries. It defaults to
+// 25 MiB, the largest payload GitHub sends.
+func WithMaxBodyBytes(n int64) ForwarderOption {
+	return func(f *Forwarder) {
+		if n > 0 {
+			f.maxBodyBytes = n
+		}
+	}
+}
+
+// WithAllowUnsigned lets NewForwarder accept an empty secret, in which case
+// incoming deliveries are forwarded without verifying their signature. Because
+// each forwarded copy is re-signed for its destination, this must only be used
+// when the forwarder is unreachable from untrusted networks.
+func WithAllowUnsigned() ForwarderOption {
+	return func(f *Forwarder) {
+		f.allowUnsigned = true
+	}
+}
+
+// ErrNoSecret is returned by NewForwarder when no secret is supplied and
+// WithAllowUnsigned is not set.
+var ErrNoSecret = errors.New("webhook secret is required to verify incoming deliveries")
+
+// Forwarder verifies incoming GitHub deliveries and fans them out to a set of
+// destinations, re-signing the body with each destination's own secret.
+type Forwarder struct {
+	handler       *Handler
+	destinations  []Destination
+	client        *http.Client
+	attempts      int
+	backoff       time.Duration
+	maxBodyBytes  int64
+	allowUnsigned bool
+	onResult      func(string, []ForwardResult)
+
+	// pending tracks deliveries still being forwarded in the background
+	pending sync.WaitGroup
+}
+
+// NewForwarder creates a forwarder that verifies incoming deliveries with
+// secret and forwards them to destinations. It returns ErrNoSecret if secret
+// is empty, unless WithAllowUnsigned is given, and an error if a destination
+// is invalid.
+func NewForwarder(secret string, destinations []Destination, opts ...ForwarderOption) (*Forwarder, error) {
+	f := &Forwarder{
+		handler:      NewHandler(secret),
+		destinations: destinations,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		attempts:     3,
+		backoff:      500 * time.Millisecond,
+		maxBodyBytes: 25 << 20,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if secret == "" && !f.allowUnsigned {
+		return nil, ErrNoSecret
+	}
+	for i := range f.destinations {
+		if err := f.destinations[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// delivery is a verified incoming delivery and the destinations it goes to.
+type delivery struct {
+	id      string
+	header  http.Header
+	body    []byte
+	targets []*Destination
+}
+
+// receive reads and verifies an incoming delivery and selects its destinations.
+func (f *Forwarder) receive(w http.ResponseWriter, r *http.Request) (*delivery, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, f.maxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	if err := f.handler.ValidateSignature(r, body); err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+
+	eventType := github.WebhookEventType(r.Header.Get(EventTypeHeader))
+	if eventType == "" {
+		return nil, errors.New("missing event type header")
+	}
+
+	// Only the common fields are needed for routing
+	var common github.WebhookPayload
+	if err := json.Unmarshal(body, &common); err != nil {
+		return nil, fmt.Errorf("error unmarshaling payload: %v", err)
+	}
+
+	d := &delivery{
+		id:     r.Header.Get(DeliveryIDHeader),
+		header: r.Header.Clone(),
+		body:   body,
+	}
+	for i := range f.destinations {
+		dest := &f.destinations[i]
+		if dest.Matches(eventType, common.Action, common.Repository.FullName) {
+			d.targets = append(d.targets, dest)
+		}
+	}
+	return d, nil
+}
+
+// send forwards a delivery to all of its destinations in parallel and reports
+// the results to the result handler.
+func (f *Forwarder) send(ctx context.Context, d *delivery) []ForwardResult {
+	results := make([]ForwardResult, len(d.targets))
+	var wg sync.WaitGroup
+	for i, dest := range d.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = f.deliver(ctx, dest, d.header, d.body)
+		}()
+	}
+	wg.Wait()
+
+	if f.onResult != nil {
+		f.onResult(d.id, results)
+	}
+	return results
+}
+
+// Forward verifies a delivery and forwards it to every matching destination in
+// parallel, waiting for all of them. Retries are bound to the request's
+// context. It returns an error only if the delivery itself is invalid; the
+// outcome for each destination is reported in the results.
+//
+// ServeHTTP does not wait: it acknowledges the delivery as soon as it has been
+// verified and forwards it in the background.
+func (f *Forwarder) Forward(r *http.Request) ([]ForwardResult, error) {
+	d, err := f.receive(nil, r)
+	if err != nil {
+		return nil, err
+	}
+	return f.send(r.Context(), d), nil
+}
+
+// Wait blocks until every delivery accepted by ServeHTTP has been forwarded,
+// including retries. Call it after the HTTP server has shut down.
+func (f *Forwarder) Wait() {
+	f.pending.Wait()
+}
+
+// deliver sends a delivery to a single destination, retrying with exponential
+// backoff on network errors, 429 and 5xx responses.
+func (f *Forwarder) deliver(ctx context.Context, d *Destination, header http.Header, body []byte) ForwardResult {
+	result := ForwardResult{Destination: d.Name}
+	start := time.Now()
+	backoff := f.backoff
+
+	for result.Attempts < f.attempts {
+		if result.Attempts > 0 {
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				result.Err = ctx.Err()
+				result.Error = result.Err.Error()
+				result.Duration = time.Since(start)
+				return result
+			}
+		}
+		result.Attempts++
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+		if err != nil {
+			// A bad URL will not fix itself, so don't retry
+			result.Err = err
+			break
+		}
+		copyForwardedHeaders(req.Header, header)
+		if d.Secret != "" {
+			req.Header.Set(SignatureHeader256, SignPayload([]byte(d.Secret), body))
+		}
+
+		resp, err := f.client.Do(req)
+		if err != nil {
+			result.Err = err
+			continue
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		result.StatusCode = resp.StatusCode
+		switch {
+		case resp.StatusCode < 300:
+			result.Err = nil
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			result.Err = fmt.Errorf("destination returned %s", resp.Status)
+			continue
+		default:
+			result.Err = fmt.Errorf("destination returned %s", resp.Status)
+		}
+		break
+	}
+
+	if result.Err != nil {
+		result.Error = result.Err.Error()
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// copyForwardedHeaders copies the X-GitHub-* headers and content metadata of
+// the original delivery. Incoming signatures are dropped because the body is
+// re-signed for each destination.
+func copyForwardedHeaders(dst, src http.Header) {
+	for name, values := range src {
+		if strings.HasPrefix(http.CanonicalHeaderKey(name), "X-Github-") {
+			dst[name] = append([]string(nil), values...)
+		}
+	}
+	for _, name := range []string{"Content-Type", "User-Agent"} {
+		if v := src.Get(name); v != "" {
+			dst.Set(name, v)
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler. Once a delivery has been verified it
+// responds with 202 Accepted and the names of the matching destinations, then
+// forwards the delivery in the background so that GitHub's delivery timeout is
+// never exceeded and a failing destination does not cause GitHub to redeliver
+// to the others. Per-destination outcomes are reported to the result handler.
+func (f *Forwarder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d, err := f.receive(w, r)
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	names := make([]string, len(d.targets))
+	for i, dest := range d.targets {
+		names[i] = dest.Name
+	}
+
+	f.pending.Add(1)
+	go func() {
+		defer f.pending.Done()
+		// The delivery must outlive the request, which is done once we respond
+		f.send(context.WithoutCancel(r.Context()), d)
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(struct {
+		Destinations []string `json:"destinations"`
+	}{names})
+}
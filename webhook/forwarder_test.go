@@ -0,0 +1,304 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+const forwarderSecret = "incoming-secret"
+
+// received is a request captured by a test destination.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// destinationServer records every request it receives and answers with the
+// next status from statuses, repeating the last one once they run out.
+type destinationServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []received
+	statuses []int
+}
+
+func newDestinationServer(t *testing.T, statuses ...int) *destinationServer {
+	t.Helper()
+	d := &destinationServer{statuses: statuses}
+	d.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		d.mu.Lock()
+		d.requests = append(d.requests, received{header: r.Header.Clone(), body: body})
+		status := http.StatusOK
+		if n := len(d.requests); len(d.statuses) > 0 {
+			status = d.statuses[min(n, len(d.statuses))-1]
+		}
+		d.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(d.Close)
+	return d
+}
+
+func (d *destinationServer) received() []received {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]received(nil), d.requests...)
+}
+
+// signedDelivery builds a delivery signed with the forwarder's secret.
+func signedDelivery(eventType github.WebhookEventType, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, string(eventType))
+	req.Header.Set(DeliveryIDHeader, "delivery-1")
+	req.Header.Set(EnterpriseHostHeader, "ghe.example.com")
+	req.Header.Set(SignatureHeader256, SignPayload([]byte(forwarderSecret), []byte(body)))
+	return req
+}
+
+func newTestForwarder(t *testing.T, destinations []Destination, opts ...ForwarderOption) *Forwarder {
+	t.Helper()
+	opts = append([]ForwarderOption{WithRetry(3, time.Millisecond)}, opts...)
+	f, err := NewForwarder(forwarderSecret, destinations, opts...)
+	if err != nil {
+		t.Fatalf("NewForwarder: %v", err)
+	}
+	return f
+}
+
+func TestNewForwarderValidation(t *testing.T) {
+	if _, err := NewForwarder("", nil); err != ErrNoSecret {
+		t.Errorf("NewForwarder without a secret = %v, want ErrNoSecret", err)
+	}
+	if _, err := NewForwarder("", nil, WithAllowUnsigned()); err != nil {
+		t.Errorf("NewForwarder with WithAllowUnsigned: %v", err)
+	}
+	if _, err := NewForwarder("s", []Destination{{Name: "a"}}); err == nil {
+		t.Error("NewForwarder accepted a destination without a url")
+	}
+	if _, err := NewForwarder("s", []Destination{{Name: "a", URL: "http://x", Repositories: []string{"["}}}); err == nil {
+		t.Error("NewForwarder accepted an invalid repository pattern")
+	}
+}
+
+func TestForwarderRejectsBadSignatures(t *testing.T) {
+	dest := newDestinationServer(t)
+	f := newTestForwarder(t, []Destination{{Name: "a", URL: dest.URL}})
+
+	body := `{"action":"opened"}`
+	for name, mutate := range map[string]func(*http.Request){
+		"missing":   func(r *http.Request) { r.Header.Del(SignatureHeader256) },
+		"wrong":     func(r *http.Request) { r.Header.Set(SignatureHeader256, SignPayload([]byte("other"), []byte(body))) },
+		"malformed": func(r *http.Request) { r.Header.Set(SignatureHeader256, "sha256=zz") },
+	} {
+		req := signedDelivery(github.IssuesEvent, body)
+		mutate(req)
+		rec := httptest.NewRecorder()
+		f.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s signature: status = %d, want 400", name, rec.Code)
+		}
+	}
+
+	f.Wait()
+	if n := len(dest.received()); n != 0 {
+		t.Fatalf("destination received %d deliveries, want 0", n)
+	}
+}
+
+func TestForwarderRejectsOversizedBodies(t *testing.T) {
+	f := newTestForwarder(t, nil, WithMaxBodyBytes(16))
+
+	rec := httptest.NewRecorder()
+	f.ServeHTTP(rec, signedDelivery(github.IssuesEvent, `{"action":"opened","padding":"0123456789"}`))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413", rec.Code)
+	}
+}
+
+func TestForwarderResignsAndKeepsGitHubHeaders(t *testing.T) {
+	first := newDestinationServer(t)
+	second := newDestinationServer(t)
+	f := newTestForwarder(t, []Destination{
+		{Name: "first", URL: first.URL, Secret: "first-secret"},
+		{Name: "second", URL: second.URL, Secret: "second-secret"},
+	})
+
+	body := `{"action":"opened","repository":{"full_name":"octo/hello"}}`
+	results, err := f.Forward(signedDelivery(github.IssuesEvent, body))
+	if err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+	for _, r := range results {
+		if !r.OK() || r.Attempts != 1 {
+			t.Errorf("result for %s = %+v, want success on the first attempt", r.Destination, r)
+		}
+	}
+
+	for secret, dest := range map[string]*destinationServer{"first-secret": first, "second-secret": second} {
+		got := dest.received()
+		if len(got) != 1 {
+			t.Fatalf("destination received %d deliveries, want 1", len(got))
+		}
+		req := got[0]
+		if string(req.body) != body {
+			t.Errorf("forwarded body = %s, want %s", req.body, body)
+		}
+
+		check := httptest.NewRequest(http.MethodPost, "/", nil)
+		check.Header = req.header
+		if err := NewHandler(secret).ValidateSignature(check, req.body); err != nil {
+			t.Errorf("signature does not verify with the destination secret: %v", err)
+		}
+		if req.header.Get(SignatureHeader256) == SignPayload([]byte(forwarderSecret), []byte(body)) {
+			t.Error("the incoming signature was forwarded")
+		}
+
+		for _, name := range []string{EventTypeHeader, DeliveryIDHeader, EnterpriseHostHeader} {
+			if req.header.Get(name) == "" {
+				t.Errorf("header %s was not forwarded", name)
+			}
+		}
+		if ct := req.header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+	}
+}
+
+func TestForwarderDropsSignatureWithoutDestinationSecret(t *testing.T) {
+	dest := newDestinationServer(t)
+	f := newTestForwarder(t, []Destination{{Name: "a", URL: dest.URL}})
+
+	if _, err := f.Forward(signedDelivery(github.IssuesEvent, `{"action":"opened"}`)); err != nil {
+		t.Fatalf("Forward: %v", err)
+	}
+	got := dest.received()
+	if len(got) != 1 {
+		t.Fatalf("destination received %d deliveries, want 1", len(got))
+	}
+	if sig := got[0].header.Get(SignatureHeader256); sig != "" {
+		t.Fatalf("forwarded %s = %q, want none", SignatureHeader256, sig)
+	}
+}
+
+func TestForwarderRouting(t *testing.T) {
+	dest := newDestinationServer(t)
+	f := newTestForwarder(t, []Destination{
+		{Name: "all", URL: dest.URL},
+		{Name: "push", URL: dest.URL, Events: []github.WebhookEventType{github.PushEvent}},
+		{Name: "opened", URL: dest.URL, Actions: []string{"opened"}},
+		{Name: "octo", URL: dest.URL, Repositories: []string{"octo/*"}},
+	})
+
+	for _, tc := range []struct {
+		eventType github.WebhookEventType
+		body      string
+		want      []string
+	}{
+		{github.PushEvent, `{"repository":{"full_name":"other/repo"}}`, []string{"all", "push"}},
+		{github.IssuesEvent, `{"action":"opened","repository":{"full_name":"octo/hello"}}`, []string{"all", "opened", "octo"}},
+		{github.IssuesEvent, `{"action":"closed","repository":{"full_name":"octo-fork/hello"}}`, []string{"all"}},
+	} {
+		results, err := f.Forward(signedDelivery(tc.eventType, tc.body))
+		if err != nil {
+			t.Fatalf("Forward: %v", err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Destination)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s %s went to %v, want %v", tc.eventType, tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestForwarderRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		statuses []int
+		attempts int
+		ok       bool
+	}{
+		{"5xx then success", []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}, 3, true},
+		{"429 then success", []int{http.StatusTooManyRequests, http.StatusOK}, 2, true},
+		{"persistent 5xx", []int{http.StatusInternalServerError}, 3, false},
+		{"4xx", []int{http.StatusNotFound}, 1, false},
+	} {
+		dest := newDestinationServer(t, tc.statuses...)
+		f := newTestForwarder(t, []Destination{{Name: "a", URL: dest.URL}})
+
+		results, err := f.Forward(signedDelivery(github.IssuesEvent, `{"action":"opened"}`))
+		if err != nil {
+			t.Fatalf("%s: Forward: %v", tc.name, err)
+		}
+		r := results[0]
+		if r.Attempts != tc.attempts || r.OK() != tc.ok {
+			t.Errorf("%s: attempts = %d, ok = %v; want %d and %v", tc.name, r.Attempts, r.OK(), tc.attempts, tc.ok)
+		}
+		if n := len(dest.received()); n != tc.attempts {
+			t.Errorf("%s: destination received %d requests, want %d", tc.name, n, tc.attempts)
+		}
+	}
+}
+
+func TestForwarderServeHTTPAcknowledgesBeforeDelivery(t *testing.T) {
+	release := make(chan struct{})
+	var delivered atomic.Int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		delivered.Add(1)
+	}))
+	defer slow.Close()
+
+	var results []ForwardResult
+	f := newTestForwarder(t, []Destination{{Name: "slow", URL: slow.URL}},
+		WithResultHandler(func(deliveryID string, r []ForwardResult) {
+			results = r
+		}))
+
+	rec := httptest.NewRecorder()
+	f.ServeHTTP(rec, signedDelivery(github.IssuesEvent, `{"action":"opened"}`))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", rec.Code)
+	}
+	var resp struct {
+		Destinations []string `json:"destinations"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || len(resp.Destinations) != 1 || resp.Destinations[0] != "slow" {
+		t.Fatalf("response = %s, want the slow destination", rec.Body)
+	}
+	if delivered.Load() != 0 {
+		t.Fatal("ServeHTTP waited for the destination")
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		f.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("Wait returned while a delivery was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-waited
+	if delivered.Load() != 1 || len(results) != 1 || !results[0].OK() {
+		t.Fatalf("delivered = %d, results = %+v; want one successful delivery", delivered.Load(), results)
+	}
+}
@@ -30,6 +30,12 @@ const (
 
 	// DeliveryIDHeader is the GitHub header containing the unique webhook delivery ID.
 	DeliveryIDHeader = "X-GitHub-Delivery"
+
+	// EnterpriseVersionHeader is the GitHub Enterprise Server header containing the server version.
+	EnterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+
+	// EnterpriseHostHeader is the GitHub Enterprise Server header containing the server hostname.
+	EnterpriseHostHeader = "X-GitHub-Enterprise-Host"
 )
 
 // Handler processes webhook requests from GitHub.
@@ -149,116 +155,9 @@ func (h *Handler) ProcessWebhook(r *http.Request) (*github.WebhookEvent, error)
 		return nil, errors.New("missing delivery ID header")
 	}
 
-	// Parse the payload based on the event type
-	var parsedPayload any
-	switch eventType {
-	case github.CheckRunEvent:
-		parsedPayload = &github.CheckRunPayload{}
-	case github.CheckSuiteEvent:
-		parsedPayload = &github.CheckSuitePayload{}
-	case github.CommitCommentEvent:
-		parsedPayload = &github.CommitCommentPayload{}
-	case github.ContentReferenceEvent:
-		parsedPayload = &github.ContentReferencePayload{}
-	case github.CreateEvent:
-		parsedPayload = &github.CreatePayload{}
-	case github.DeleteEvent:
-		parsedPayload = &github.DeletePayload{}
-	case github.DeployKeyEvent:
-		parsedPayload = &github.DeployKeyPayload{}
-	case github.DeploymentEvent:
-		parsedPayload = &github.DeploymentPayload{}
-	case github.DeploymentStatusEvent:
-		parsedPayload = &github.DeploymentStatusPayload{}
-	case github.DiscussionEvent:
-		parsedPayload = &github.DiscussionPayload{}
-	case github.DiscussionCommentEvent:
-		parsedPayload = &github.DiscussionCommentPayload{}
-	case github.ForkEvent:
-		parsedPayload = &github.ForkPayload{}
-	case github.GitHubAppAuthorizationEvent:
-		parsedPayload = &github.AppAuthorizationPayload{}
-	case github.GollumEvent:
-		parsedPayload = &github.GollumPayload{}
-	case github.InstallationEvent:
-		parsedPayload = &github.InstallationPayload{}
-	case github.InstallationRepositoriesEvent:
-		parsedPayload = &github.InstallationRepositoriesPayload{}
-	case github.IssueCommentEvent:
-		parsedPayload = &github.IssueCommentPayload{}
-	case github.IssuesEvent:
-		parsedPayload = &github.IssuesPayload{}
-	case github.LabelEvent:
-		parsedPayload = &github.LabelPayload{}
-	case github.MarketplacePurchaseEvent:
-		parsedPayload = &github.MarketplacePurchasePayload{}
-	case github.MemberEvent:
-		parsedPayload = &github.MemberPayload{}
-	case github.MembershipEvent:
-		parsedPayload = &github.MembershipPayload{}
-	case github.MetaEvent:
-		parsedPayload = &github.MetaPayload{}
-	case github.MilestoneEvent:
-		parsedPayload = &github.MilestonePayload{}
-	case github.OrganizationEvent:
-		parsedPayload = &github.OrganizationPayload{}
-	case github.OrgBlockEvent:
-		parsedPayload = &github.OrgBlockPayload{}
-	case github.PackageEvent:
-		parsedPayload = &github.PackagePayload{}
-	case github.PageBuildEvent:
-		parsedPayload = &github.PageBuildPayload{}
-	case github.PingEvent:
-		parsedPayload = &github.PingPayload{}
-	case github.ProjectEvent:
-		parsedPayload = &github.ProjectPayload{}
-	case github.ProjectCardEvent:
-		parsedPayload = &github.ProjectCardPayload{}
-	case github.ProjectColumnEvent:
-		parsedPayload = &github.ProjectColumnPayload{}
-	case github.PublicEvent:
-		parsedPayload = &github.PublicPayload{}
-	case github.PullRequestEvent:
-		parsedPayload = &github.PullRequestPayload{}
-	case github.PullRequestReviewEvent:
-		parsedPayload = &github.PullRequestReviewPayload{}
-	case github.PullRequestReviewCommentEvent:
-		parsedPayload = &github.PullRequestReviewCommentPayload{}
-	case github.PushEvent:
-		parsedPayload = &github.PushPayload{}
-	case github.ReleaseEvent:
-		parsedPayload = &github.ReleasePayload{}
-	case github.RepositoryDispatchEvent:
-		parsedPayload = &github.RepositoryDispatchPayload{}
-	case github.RepositoryEvent:
-		parsedPayload = &github.RepositoryPayload{}
-	case github.RepositoryImportEvent:
-		parsedPayload = &github.RepositoryImportPayload{}
-	case github.RepositoryVulnerabilityAlertEvent:
-		parsedPayload = &github.RepositoryVulnerabilityAlertPayload{}
-	case github.SecurityAdvisoryEvent:
-		parsedPayload = &github.SecurityAdvisoryPayload{}
-	case github.SponsorshipEvent:
-		parsedPayload = &github.SponsorshipPayload{}
-	case github.StarEvent:
-		parsedPayload = &github.StarPayload{}
-	case github.StatusEvent:
-		parsedPayload = &github.StatusPayload{}
-	case github.TeamEvent:
-		parsedPayload = &github.TeamPayload{}
-	case github.TeamAddEvent:
-		parsedPayload = &github.TeamAddPayload{}
-	case github.WatchEvent:
-		parsedPayload = &github.WatchPayload{}
-	case github.WorkflowDispatchEvent:
-		parsedPayload = &github.WorkflowDispatchPayload{}
-	case github.WorkflowJobEvent:
-		parsedPayload = &github.WorkflowJobPayload{}
-	case github.WorkflowRunEvent:
-		parsedPayload = &github.WorkflowRunPayload{}
-	default:
-		parsedPayload = &github.WebhookPayload{}
-	}
+	// Parse the payload into the type registered for the event, falling back
+	// to the common fields for unknown events
+	parsedPayload := github.NewPayload(eventType)
 
 	// Unmarshal the payload
 	if err := json.Unmarshal(payload, parsedPayload); err != nil {
@@ -266,9 +165,11 @@ func (h *Handler) ProcessWebhook(r *http.Request) (*github.WebhookEvent, error)
 	}
 
 	webhookEvent = &github.WebhookEvent{
-		Type:       eventType,
-		DeliveryID: deliveryID,
-		Payload:    parsedPayload,
+		Type:              eventType,
+		DeliveryID:        deliveryID,
+		Payload:           parsedPayload,
+		EnterpriseVersion: r.Header.Get(EnterpriseVersionHeader),
+		EnterpriseHost:    r.Header.Get(EnterpriseHostHeader),
 	}
 	return webhookEvent, retErr
 }
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+)
+
+// newDelivery builds a delivery signed with secret.
+func newDelivery(secret string, eventType github.WebhookEventType, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set(EventTypeHeader, string(eventType))
+	req.Header.Set(DeliveryIDHeader, "delivery-1")
+	req.Header.Set(SignatureHeader256, SignPayload([]byte(secret), []byte(body)))
+	return req
+}
+
+func TestProcessWebhookRegistryPackage(t *testing.T) {
+	body := `{
+		"action": "published",
+		"registry_package": {
+			"id": 42,
+			"name": "hello",
+			"package_type": "CONTAINER",
+			"package_version": {"id": 7, "version": "1.2.3"}
+		},
+		"repository": {"full_name": "octo/hello"}
+	}`
+
+	event, err := NewHandler("s3cret").ProcessWebhook(newDelivery("s3cret", github.RegistryPackageEvent, body))
+	if err != nil {
+		t.Fatalf("ProcessWebhook: %v", err)
+	}
+	payload, ok := event.Payload.(*github.RegistryPackagePayload)
+	if !ok {
+		t.Fatalf("Payload is %T, want *github.RegistryPackagePayload", event.Payload)
+	}
+	if payload.Action != "published" || payload.RegistryPackage.Name != "hello" || payload.RegistryPackage.PackageVersion.Version != "1.2.3" {
+		t.Fatalf("decoded payload = %+v", payload)
+	}
+	if event.Type != github.RegistryPackageEvent || event.DeliveryID != "delivery-1" {
+		t.Fatalf("event = %+v", event)
+	}
+}
+
+func TestProcessWebhookUsesPayloadRegistry(t *testing.T) {
+	for _, eventType := range []github.WebhookEventType{
+		github.IssuesEvent,
+		github.PushEvent,
+		github.WorkflowRunEvent,
+		"some_future_event",
+	} {
+		event, err := NewHandler("s3cret").ProcessWebhook(newDelivery("s3cret", eventType, `{"action":"x"}`))
+		if err != nil {
+			t.Fatalf("%s: ProcessWebhook: %v", eventType, err)
+		}
+		if got, want := fmt.Sprintf("%T", event.Payload), fmt.Sprintf("%T", github.NewPayload(eventType)); got != want {
+			t.Errorf("%s: Payload is %s, want %s", eventType, got, want)
+		}
+	}
+}
+
+func TestProcessWebhookRejectsBadSignature(t *testing.T) {
+	req := newDelivery("other", github.PingEvent, `{"zen":"hi"}`)
+	if _, err := NewHandler("s3cret").ProcessWebhook(req); err == nil {
+		t.Fatal("ProcessWebhook accepted a delivery signed with the wrong secret")
+	}
+}
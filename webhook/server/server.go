@@ -0,0 +1,392 @@
+// Package server runs a webhook handler behind a production-ready HTTP server
+// with hardened timeouts, health and readiness endpoints and graceful shutdown.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+	"github.com/ren3gadem4rm0t/github-hook-types-go/webhook"
+)
+
+// Config holds the server settings. Zero values are replaced with the defaults
+// documented on each field.
+type Config struct {
+	// Addr is the address to listen on. Defaults to ":3000".
+	Addr string
+	// Path is the route deliveries are received on. Defaults to "/webhook".
+	Path string
+	// Secret is the webhook secret used to validate signatures.
+	Secret string
+	// AllowUnsigned lets the server run without a secret, accepting unsigned
+	// deliveries. Without it New refuses a config with no secret so an
+	// unprotected endpoint is never put into service by accident. The
+	// readiness check still fails while no secret is configured.
+	AllowUnsigned bool
+
+	// ReadHeaderTimeout defaults to 10 seconds.
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout defaults to 30 seconds.
+	ReadTimeout time.Duration
+	// WriteTimeout defaults to 30 seconds.
+	WriteTimeout time.Duration
+	// IdleTimeout defaults to 2 minutes.
+	IdleTimeout time.Duration
+	// ShutdownTimeout bounds how long in-flight requests and queued events are
+	// given to finish after a shutdown signal. Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+	// DrainDelay is how long the server keeps accepting deliveries after a
+	// shutdown signal while /readyz reports it unavailable, giving load
+	// balancers time to stop routing traffic to it before the listener closes.
+	// It counts against ShutdownTimeout. Zero closes the listener immediately.
+	DrainDelay time.Duration
+
+	// MaxHeaderBytes defaults to 64 KiB.
+	MaxHeaderBytes int
+	// MaxBodyBytes defaults to 25 MiB, the largest payload GitHub sends.
+	MaxBodyBytes int64
+	// MaxQueueBacklog fails the readiness check when the dispatcher has more
+	// pending events than this. Zero disables the check.
+	MaxQueueBacklog int
+}
+
+// ConfigFromEnv builds a Config from environment variables:
+//
+//	PORT                     listen port (Addr becomes ":PORT")
+//	WEBHOOK_ADDR             listen address, overrides PORT
+//	WEBHOOK_PATH             route deliveries are received on
+//	WEBHOOK_SECRET           webhook secret
+//	WEBHOOK_ALLOW_UNSIGNED   "true" to allow running without a secret
+//	WEBHOOK_READ_TIMEOUT     duration, e.g. "30s"
+//	WEBHOOK_WRITE_TIMEOUT    duration
+//	WEBHOOK_IDLE_TIMEOUT     duration
+//	WEBHOOK_SHUTDOWN_TIMEOUT duration
+//	WEBHOOK_DRAIN_DELAY      duration
+//	WEBHOOK_MAX_BODY_BYTES   integer
+//	WEBHOOK_MAX_QUEUE        integer
+//
+// Unset variables leave the corresponding field at its zero value so the
+// defaults apply.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Addr:   os.Getenv("WEBHOOK_ADDR"),
+		Path:   os.Getenv("WEBHOOK_PATH"),
+		Secret: os.Getenv("WEBHOOK_SECRET"),
+	}
+	if cfg.Addr == "" {
+		if port := os.Getenv("PORT"); port != "" {
+			cfg.Addr = ":" + port
+		}
+	}
+
+	if v := os.Getenv("WEBHOOK_ALLOW_UNSIGNED"); v != "" {
+		allow, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid WEBHOOK_ALLOW_UNSIGNED: %v", err)
+		}
+		cfg.AllowUnsigned = allow
+	}
+
+	durations := []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"WEBHOOK_READ_TIMEOUT", &cfg.ReadTimeout},
+		{"WEBHOOK_WRITE_TIMEOUT", &cfg.WriteTimeout},
+		{"WEBHOOK_IDLE_TIMEOUT", &cfg.IdleTimeout},
+		{"WEBHOOK_SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout},
+		{"WEBHOOK_DRAIN_DELAY", &cfg.DrainDelay},
+	}
+	for _, d := range durations {
+		v := os.Getenv(d.name)
+		if v == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s: %v", d.name, err)
+		}
+		*d.dst = parsed
+	}
+
+	if v := os.Getenv("WEBHOOK_MAX_BODY_BYTES"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid WEBHOOK_MAX_BODY_BYTES: %v", err)
+		}
+		cfg.MaxBodyBytes = n
+	}
+	if v := os.Getenv("WEBHOOK_MAX_QUEUE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid WEBHOOK_MAX_QUEUE: %v", err)
+		}
+		cfg.MaxQueueBacklog = n
+	}
+
+	return cfg, nil
+}
+
+// withDefaults returns a copy of the config with zero values replaced.
+func (c Config) withDefaults() Config {
+	if c.Addr == "" {
+		c.Addr = ":3000"
+	}
+	if c.Path == "" {
+		c.Path = "/webhook"
+	}
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = 10 * time.Second
+	}
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = 30 * time.Second
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = 30 * time.Second
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = 2 * time.Minute
+	}
+	if c.ShutdownTimeout == 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+	if c.MaxHeaderBytes == 0 {
+		c.MaxHeaderBytes = 64 << 10
+	}
+	if c.MaxBodyBytes == 0 {
+		c.MaxBodyBytes = 25 << 20
+	}
+	return c
+}
+
+// Check is a named readiness check. It returns nil when healthy.
+type Check func() error
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithDispatcher processes events through a dispatcher. Its backlog is
+// reported by the readiness check and it is drained on shutdown.
+func WithDispatcher(d *webhook.Dispatcher) Option {
+	return func(s *Server) {
+		s.dispatcher = d
+	}
+}
+
+// WithReadinessCheck adds a named check to the readiness endpoint.
+func WithReadinessCheck(name string, check Check) Option {
+	return func(s *Server) {
+		s.checks[name] = check
+	}
+}
+
+// Server serves a webhook endpoint alongside /healthz and /readyz.
+type Server struct {
+	cfg        Config
+	httpServer *http.Server
+	dispatcher *webhook.Dispatcher
+	checks     map[string]Check
+
+	mu           sync.Mutex
+	shuttingDown atomic.Bool
+}
+
+// ErrNoSecret is returned by New when the config has no secret and
+// AllowUnsigned is not set.
+var ErrNoSecret = errors.New("webhook secret is not configured and unsigned deliveries are not allowed")
+
+// ErrNoCallback is returned by New when neither a callback nor a dispatcher is supplied.
+var ErrNoCallback = errors.New("a callback or a dispatcher is required")
+
+// New creates a server that passes every verified event to callback. When a
+// dispatcher is supplied with WithDispatcher, events are handed to it instead
+// and callback may be nil. It returns ErrNoSecret if the config has no secret
+// and does not allow unsigned deliveries, and ErrNoCallback if there is
+// nothing to hand events to.
+func New(cfg Config, callback func(*github.WebhookEvent) error, opts ...Option) (*Server, error) {
+	s := &Server{
+		cfg:    cfg.withDefaults(),
+		checks: make(map[string]Check),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.cfg.Secret == "" && !s.cfg.AllowUnsigned {
+		return nil, ErrNoSecret
+	}
+	if s.dispatcher != nil {
+		callback = s.dispatcher.Dispatch
+	}
+	if callback == nil {
+		return nil, ErrNoCallback
+	}
+
+	s.checks["secret"] = s.checkSecret
+	if s.dispatcher != nil && s.cfg.MaxQueueBacklog > 0 {
+		s.checks["queue"] = s.checkQueue
+	}
+
+	handler := webhook.NewHandler(s.cfg.Secret).HandleWebhook(callback)
+
+	mux := http.NewServeMux()
+	mux.Handle(s.cfg.Path, http.MaxBytesHandler(handler, s.cfg.MaxBodyBytes))
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/readyz", s.handleReady)
+
+	s.httpServer = &http.Server{
+		Addr:              s.cfg.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: s.cfg.ReadHeaderTimeout,
+		ReadTimeout:       s.cfg.ReadTimeout,
+		WriteTimeout:      s.cfg.WriteTimeout,
+		IdleTimeout:       s.cfg.IdleTimeout,
+		MaxHeaderBytes:    s.cfg.MaxHeaderBytes,
+	}
+
+	return s, nil
+}
+
+// Handler returns the server's HTTP handler, including the health endpoints.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// checkSecret fails when no secret is configured, so a server running with
+// AllowUnsigned is never reported as ready.
+func (s *Server) checkSecret() error {
+	if s.cfg.Secret == "" {
+		return errors.New("webhook secret is not configured")
+	}
+	return nil
+}
+
+// checkQueue fails when the dispatcher backlog exceeds the configured limit.
+func (s *Server) checkQueue() error {
+	if pending := s.dispatcher.Stats().Pending; pending > s.cfg.MaxQueueBacklog {
+		return fmt.Errorf("%d events pending, limit is %d", pending, s.cfg.MaxQueueBacklog)
+	}
+	return nil
+}
+
+// handleHealth reports liveness. The process is alive as long as it can answer.
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, http.StatusOK, nil)
+}
+
+// handleReady reports whether the server should receive traffic.
+func (s *Server) handleReady(w http.ResponseWriter, _ *http.Request) {
+	failures := make(map[string]string)
+	if s.shuttingDown.Load() {
+		failures["shutdown"] = "server is shutting down"
+	}
+	for name, check := range s.checks {
+		if err := check(); err != nil {
+			failures[name] = err.Error()
+		}
+	}
+
+	if len(failures) > 0 {
+		writeStatus(w, http.StatusServiceUnavailable, failures)
+		return
+	}
+	writeStatus(w, http.StatusOK, nil)
+}
+
+// writeStatus writes a health response as JSON.
+func writeStatus(w http.ResponseWriter, code int, failures map[string]string) {
+	body := struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks,omitempty"`
+	}{Status: "ok", Checks: failures}
+	if code != http.StatusOK {
+		body.Status = "unavailable"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Run serves until ctx is cancelled and then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+	return s.Shutdown(shutdownCtx)
+}
+
+// ListenAndServe serves until the process receives SIGINT or SIGTERM and then
+// shuts down gracefully.
+func (s *Server) ListenAndServe() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return s.Run(ctx)
+}
+
+// Shutdown marks the server as not ready, keeps serving for the configured
+// drain delay, stops accepting connections, waits for in-flight requests and
+// then drains the dispatcher, all bounded by ctx.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shuttingDown.Store(true)
+
+	if s.cfg.DrainDelay > 0 {
+		timer := time.NewTimer(s.cfg.DrainDelay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	if s.dispatcher == nil {
+		return nil
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.dispatcher.Close()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out draining queued events: %v", ctx.Err())
+	}
+}
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ren3gadem4rm0t/github-hook-types-go"
+	"github.com/ren3gadem4rm0t/github-hook-types-go/webhook"
+)
+
+func noop(*github.WebhookEvent) error { return nil }
+
+// readiness fetches /readyz and returns the status code and failing checks.
+func readiness(t *testing.T, s *Server) (int, map[string]string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var body struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding /readyz response %q: %v", rec.Body, err)
+	}
+	return rec.Code, body.Checks
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("WEBHOOK_PATH", "/hooks")
+	t.Setenv("WEBHOOK_SECRET", "s3cret")
+	t.Setenv("WEBHOOK_ALLOW_UNSIGNED", "true")
+	t.Setenv("WEBHOOK_READ_TIMEOUT", "5s")
+	t.Setenv("WEBHOOK_WRITE_TIMEOUT", "6s")
+	t.Setenv("WEBHOOK_IDLE_TIMEOUT", "7s")
+	t.Setenv("WEBHOOK_SHUTDOWN_TIMEOUT", "8s")
+	t.Setenv("WEBHOOK_DRAIN_DELAY", "2s")
+	t.Setenv("WEBHOOK_MAX_BODY_BYTES", "1024")
+	t.Setenv("WEBHOOK_MAX_QUEUE", "50")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("ConfigFromEnv: %v", err)
+	}
+	want := Config{
+		Addr:            ":8080",
+		Path:            "/hooks",
+		Secret:          "s3cret",
+		AllowUnsigned:   true,
+		ReadTimeout:     5 * time.Second,
+		WriteTimeout:    6 * time.Second,
+		IdleTimeout:     7 * time.Second,
+		ShutdownTimeout: 8 * time.Second,
+		DrainDelay:      2 * time.Second,
+		MaxBodyBytes:    1024,
+		MaxQueueBacklog: 50,
+	}
+	if cfg != want {
+		t.Fatalf("ConfigFromEnv =\n%+v\nwant\n%+v", cfg, want)
+	}
+
+	t.Setenv("WEBHOOK_ADDR", "127.0.0.1:9000")
+	if cfg, _ := ConfigFromEnv(); cfg.Addr != "127.0.0.1:9000" {
+		t.Fatalf("Addr = %q, want WEBHOOK_ADDR to override PORT", cfg.Addr)
+	}
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	for name, value := range map[string]string{
+		"WEBHOOK_ALLOW_UNSIGNED":   "maybe",
+		"WEBHOOK_READ_TIMEOUT":     "5",
+		"WEBHOOK_WRITE_TIMEOUT":    "soon",
+		"WEBHOOK_IDLE_TIMEOUT":     "1y",
+		"WEBHOOK_SHUTDOWN_TIMEOUT": "x",
+		"WEBHOOK_DRAIN_DELAY":      "-",
+		"WEBHOOK_MAX_BODY_BYTES":   "1MB",
+		"WEBHOOK_MAX_QUEUE":        "many",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			_, err := ConfigFromEnv()
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Fatalf("ConfigFromEnv with %s=%q = %v, want an error naming the variable", name, value, err)
+			}
+		})
+	}
+}
+
+func TestConfigWithDefaults(t *testing.T) {
+	cfg := Config{}.withDefaults()
+	want := Config{
+		Addr:              ":3000",
+		Path:              "/webhook",
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		ShutdownTimeout:   30 * time.Second,
+		MaxHeaderBytes:    64 << 10,
+		MaxBodyBytes:      25 << 20,
+	}
+	if cfg != want {
+		t.Fatalf("withDefaults =\n%+v\nwant\n%+v", cfg, want)
+	}
+
+	custom := Config{Addr: ":1", Path: "/p", ShutdownTimeout: time.Second, MaxBodyBytes: 10}.withDefaults()
+	if custom.Addr != ":1" || custom.Path != "/p" || custom.ShutdownTimeout != time.Second || custom.MaxBodyBytes != 10 {
+		t.Fatalf("withDefaults overwrote set fields: %+v", custom)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New(Config{}, noop); err != ErrNoSecret {
+		t.Errorf("New without a secret = %v, want ErrNoSecret", err)
+	}
+	if _, err := New(Config{Secret: "s"}, nil); err != ErrNoCallback {
+		t.Errorf("New without a callback = %v, want ErrNoCallback", err)
+	}
+	if _, err := New(Config{AllowUnsigned: true}, noop); err != nil {
+		t.Errorf("New with AllowUnsigned: %v", err)
+	}
+
+	d := webhook.NewDispatcher(noop)
+	defer d.Close()
+	if _, err := New(Config{Secret: "s"}, nil, WithDispatcher(d)); err != nil {
+		t.Errorf("New with a dispatcher and no callback: %v", err)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	s, err := New(Config{Secret: "s"}, noop)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"ok"`) {
+		t.Fatalf("/healthz = %d %s, want 200 ok", rec.Code, rec.Body)
+	}
+}
+
+func TestReadyzSecretCheck(t *testing.T) {
+	s, err := New(Config{Secret: "s"}, noop)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if code, checks := readiness(t, s); code != http.StatusOK {
+		t.Fatalf("/readyz with a secret = %d %v, want 200", code, checks)
+	}
+
+	unsigned, err := New(Config{AllowUnsigned: true}, noop)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	code, checks := readiness(t, unsigned)
+	if code != http.StatusServiceUnavailable || checks["secret"] == "" {
+		t.Fatalf("/readyz without a secret = %d %v, want 503 with a secret failure", code, checks)
+	}
+}
+
+func TestReadyzQueueCheck(t *testing.T) {
+	release := make(chan struct{})
+	d := webhook.NewDispatcher(func(*github.WebhookEvent) error {
+		<-release
+		return nil
+	}, webhook.WithWorkers(1))
+	defer d.Close()
+
+	s, err := New(Config{Secret: "s", MaxQueueBacklog: 1}, nil, WithDispatcher(d))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := d.Dispatch(&github.WebhookEvent{}); err != nil {
+			t.Fatalf("Dispatch: %v", err)
+		}
+	}
+	code, checks := readiness(t, s)
+	if code != http.StatusServiceUnavailable || checks["queue"] == "" {
+		t.Fatalf("/readyz over the backlog = %d %v, want 503 with a queue failure", code, checks)
+	}
+
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for d.Stats().Pending > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if code, checks := readiness(t, s); code != http.StatusOK {
+		t.Fatalf("/readyz after draining = %d %v, want 200", code, checks)
+	}
+}
+
+func TestShutdownDrainDelay(t *testing.T) {
+	s, err := New(Config{Addr: "127.0.0.1:0", Secret: "s", DrainDelay: 100 * time.Millisecond}, noop)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Shutdown(context.Background())
+	}()
+
+	// Readiness flips straight away while the server keeps serving
+	time.Sleep(20 * time.Millisecond)
+	code, checks := readiness(t, s)
+	if code != http.StatusServiceUnavailable || checks["shutdown"] == "" {
+		t.Fatalf("/readyz during shutdown = %d %v, want 503 with a shutdown failure", code, checks)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Fatalf("Shutdown returned after %v, before the drain delay", elapsed)
+	}
+}
+
+func TestShutdownDrainDelayHonoursContext(t *testing.T) {
+	s, err := New(Config{Addr: "127.0.0.1:0", Secret: "s", DrainDelay: time.Hour}, noop)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_ = s.Shutdown(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Shutdown waited %v despite the context deadline", elapsed)
+	}
+}
+
+func TestRunDrainsDispatcherWithinShutdownTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{"drained", time.Second, false},
+		{"timed out", 20 * time.Millisecond, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var processed atomic.Int32
+			d := webhook.NewDispatcher(func(*github.WebhookEvent) error {
+				time.Sleep(150 * time.Millisecond)
+				processed.Add(1)
+				return nil
+			})
+
+			s, err := New(Config{Addr: "127.0.0.1:0", Secret: "s", ShutdownTimeout: tc.timeout}, nil, WithDispatcher(d))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if err := d.Dispatch(&github.WebhookEvent{}); err != nil {
+				t.Fatalf("Dispatch: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err = s.Run(ctx)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Run = %v, want error: %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && processed.Load() != 1 {
+				t.Fatalf("processed %d events before Run returned, want 1", processed.Load())
+			}
+			d.Close()
+		})
+	}
+}